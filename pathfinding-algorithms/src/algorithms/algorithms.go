@@ -1,17 +1,61 @@
 package algorithms
 
-import "pathfinding-algorithms/models"
+import (
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
 
 type PathfindingAlgorithm interface {
 	Init(width, height int) error
 	Clear() error
 	FindPath() error
 	GetGrid() (*models.Grid, error)
-	GetSnapshot() ([][]*models.Node, error)
-	GetPath() (map[models.Node]models.Node, error)
+	GetSnapshot() (*models.Snapshot, error)
+	GetPath() ([]models.Node, error)
 	SetStart(x, y int) error
 	SetEnd(x, y int) error
 	SetWall(x, y int, visited bool) error
 	GetStart() (node *models.Node, err error)
 	GetEnd() (node *models.Node, err error)
+	AddEnd(x, y int) error
+	RemoveEnd(x, y int) error
+	GetEnds() ([]*models.Node, error)
+	AddWaypoint(x, y int) error
+	RemoveWaypoint(x, y int) error
+	GetWaypoints() ([]*models.Node, error)
+}
+
+// GridInvalidator is implemented by algorithms that cache data derived from the grid, they have to be
+// notified when the grid is modified without going through SetWall
+type GridInvalidator interface {
+	Invalidate() error
+}
+
+// SnapshotRecorder is implemented by algorithms that can queue snapshots of changes made outside the search,
+// so the UI replays them like the search itself
+type SnapshotRecorder interface {
+	Record() error
+}
+
+// HierarchicalAlgorithm is implemented by algorithms that search an abstraction of the grid
+type HierarchicalAlgorithm interface {
+	GetHierarchy() (*Hierarchy, error)
+}
+
+// LandmarkAlgorithm is implemented by algorithms that can use the ALT landmark heuristic
+type LandmarkAlgorithm interface {
+	SelectLandmarks(count int) error
+	AddLandmark(x, y int) error
+	ClearLandmarks() error
+	GetLandmarks() ([]*models.Node, error)
+}
+
+// TieBreakingAlgorithm is implemented by algorithms that let the order of equally good open nodes be chosen
+type TieBreakingAlgorithm interface {
+	SetTieBreak(tieBreak datastructures.TieBreak, seed int64) error
+}
+
+// OpenSetAlgorithm is implemented by algorithms that can keep their open nodes in any datastructures.OpenSet
+type OpenSetAlgorithm interface {
+	SetOpenSet(id datastructures.OpenSetID) error
 }
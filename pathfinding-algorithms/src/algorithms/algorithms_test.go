@@ -2,7 +2,10 @@ package algorithms_test
 
 import (
 	"fmt"
+	"math"
 	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
 
 	"testing"
 )
@@ -13,13 +16,580 @@ func TestFindPathSimple(t *testing.T) {
 	aStar.SetStart(1, 1)
 	aStar.SetEnd(18, 18)
 
-	path, _, err := aStar.FindPath()
+	err := aStar.FindPath()
 	if err != nil {
 		t.Fatalf("FindPath failed: %v", err)
 	}
+	path, err := aStar.GetPath()
+	if err != nil {
+		t.Fatalf("GetPath failed: %v", err)
+	}
 
 	if len(path) == 0 {
 		t.Fatalf("No path found when one was expected")
 	}
 	fmt.Printf("Path found: %v\n", path)
 }
+
+// pathLength checks that the path is a connected route from the start to the end and returns its number of steps
+func pathLength(t *testing.T, algorithm algorithms.PathfindingAlgorithm) int {
+	t.Helper()
+	path, err := algorithm.GetPath()
+	if err != nil {
+		t.Fatalf("GetPath failed: %v", err)
+	}
+	start, _ := algorithm.GetStart()
+	end, _ := algorithm.GetEnd()
+	if len(path) == 0 || path[0].X != start.X || path[0].Y != start.Y {
+		t.Fatalf("Path does not begin at the start")
+	}
+	if last := path[len(path)-1]; last.X != end.X || last.Y != end.Y {
+		t.Fatalf("Path does not finish at the end")
+	}
+	for i := 1; i < len(path); i++ {
+		dx, dy := path[i].X-path[i-1].X, path[i].Y-path[i-1].Y
+		if dx*dx+dy*dy != 1 || path[i].IsWall {
+			t.Fatalf("Path is broken between %v and %v", path[i-1], path[i])
+		}
+	}
+	return len(path) - 1
+}
+
+func TestHPAStarFindsPathAcrossClusters(t *testing.T) {
+	hpaStar := algorithms.HPAStar{}
+	if err := hpaStar.Init(50, 40); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	hpaStar.SetStart(2, 3)
+	hpaStar.SetEnd(45, 35)
+
+	if err := hpaStar.FindPath(); err != nil {
+		t.Fatalf("FindPath failed: %v", err)
+	}
+	// The abstraction is near-optimal, allow some detour over the Manhattan distance
+	if steps := pathLength(t, &hpaStar); steps < 75 || steps > 90 {
+		t.Fatalf("Expected a path of about 75 steps, got %d", steps)
+	}
+}
+
+func TestHPAStarUpdatesAbstractionOnWalls(t *testing.T) {
+	hpaStar := algorithms.HPAStar{}
+	if err := hpaStar.Init(30, 30); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	hpaStar.SetStart(3, 15)
+	hpaStar.SetEnd(25, 15)
+	// Wall off the column at the cluster border except for a single gap near the bottom
+	for y := 1; y < 29; y++ {
+		if y != 26 {
+			if err := hpaStar.SetWall(10, y, true); err != nil {
+				t.Fatalf("SetWall failed: %v", err)
+			}
+		}
+	}
+
+	hierarchy, err := hpaStar.GetHierarchy()
+	if err != nil {
+		t.Fatalf("GetHierarchy failed: %v", err)
+	}
+	for _, edge := range hierarchy.Edges {
+		if edge.Inter && edge.To.X == 10 && edge.To.Y != 26 {
+			t.Fatalf("Entrance through the wall at %v was not removed", edge.To)
+		}
+	}
+
+	if err := hpaStar.FindPath(); err != nil {
+		t.Fatalf("FindPath failed: %v", err)
+	}
+	if steps := pathLength(t, &hpaStar); steps < 22+2*11 {
+		t.Fatalf("Path of %d steps did not go through the gap", steps)
+	}
+}
+
+// countVisited returns the number of cells expanded by the last search
+func countVisited(t *testing.T, algorithm algorithms.PathfindingAlgorithm) int {
+	t.Helper()
+	grid, err := algorithm.GetGrid()
+	if err != nil {
+		t.Fatalf("GetGrid failed: %v", err)
+	}
+	nodes, _ := grid.GetNodes()
+	visited := 0
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.Visited {
+				visited++
+			}
+		}
+	}
+	return visited
+}
+
+func TestAStarLandmarksExpandFewerNodes(t *testing.T) {
+	search := func(landmarks int) (int, int) {
+		aStar := algorithms.AStar{}
+		aStar.Init(40, 40)
+		aStar.SetStart(5, 20)
+		aStar.SetEnd(34, 20)
+		// A long wall between start and end makes the straight-line estimate misleading
+		for y := 3; y < 39; y++ {
+			aStar.SetWall(20, y, true)
+		}
+		if err := aStar.SelectLandmarks(landmarks); err != nil {
+			t.Fatalf("SelectLandmarks failed: %v", err)
+		}
+		if err := aStar.FindPath(); err != nil {
+			t.Fatalf("FindPath failed: %v", err)
+		}
+		return pathLength(t, &aStar), countVisited(t, &aStar)
+	}
+
+	euclideanLength, euclideanVisited := search(0)
+	altLength, altVisited := search(4)
+	if altLength != euclideanLength {
+		t.Fatalf("ALT path length %d differs from %d, the heuristic is not admissible", altLength, euclideanLength)
+	}
+	if altVisited >= euclideanVisited {
+		t.Fatalf("ALT visited %d nodes, expected fewer than the %d visited with Euclidean", altVisited, euclideanVisited)
+	}
+}
+
+func TestAStarLandmarksRecomputedOnWalls(t *testing.T) {
+	aStar := algorithms.AStar{}
+	aStar.Init(20, 20)
+	aStar.AddLandmark(3, 3)
+	aStar.SelectLandmarks(2)
+	landmarks, err := aStar.GetLandmarks()
+	if err != nil {
+		t.Fatalf("GetLandmarks failed: %v", err)
+	}
+	if len(landmarks) != 2 || landmarks[0].X != 3 || landmarks[0].Y != 3 {
+		t.Fatalf("Expected the picked landmark followed by a selected one, got %v", landmarks)
+	}
+
+	aStar.SetWall(3, 3, true)
+	landmarks, _ = aStar.GetLandmarks()
+	for _, landmark := range landmarks {
+		if landmark.IsWall {
+			t.Fatalf("Landmark %v was not recomputed after becoming a wall", landmark)
+		}
+	}
+
+	aStar.AddLandmark(6, 6)
+	aStar.AddLandmark(9, 9)
+	aStar.SelectLandmarks(1)
+	if landmarks, _ = aStar.GetLandmarks(); len(landmarks) != 1 {
+		t.Fatalf("Expected a single landmark after lowering the count, got %v", landmarks)
+	}
+}
+
+func TestFlowFieldLeadsToEnd(t *testing.T) {
+	grid, _ := models.NewGrid(20, 20)
+	for y := 1; y < 15; y++ {
+		grid.SetWall(10, y, true)
+	}
+	// A heavy band is close to the end by distance, but costly to step into
+	for y := 2; y < 18; y++ {
+		grid.SetWeight(13, y, 9)
+	}
+	field, err := algorithms.NewFlowField(grid)
+	if err != nil {
+		t.Fatalf("NewFlowField failed: %v", err)
+	}
+
+	end, _ := grid.GetEnd()
+	nodes, _ := grid.GetNodes()
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.IsWall {
+				continue
+			}
+			// Following the directions from any open cell must reach the end at exactly the integrated cost
+			at, steps, cost := node, 0, 0.0
+			for at != end {
+				direction := field.Directions[at.Y][at.X]
+				if direction == (models.Point{}) || steps > 400 {
+					t.Fatalf("Flow from (%d,%d) does not reach the end", node.X, node.Y)
+				}
+				next, _ := grid.GetNode(at.X+direction.Dx, at.Y+direction.Dy)
+				at, steps, cost = next, steps+1, cost+(at.Cost()+next.Cost())/2
+			}
+			if math.Abs(cost-field.Costs[node.Y][node.X]) > 1e-9 {
+				t.Fatalf("Flow from (%d,%d) cost %v, integrated cost is %v", node.X, node.Y, cost, field.Costs[node.Y][node.X])
+			}
+		}
+	}
+}
+
+func TestWaypointsAreVisitedInOrder(t *testing.T) {
+	for _, algorithm := range []algorithms.PathfindingAlgorithm{&algorithms.AStar{}, &algorithms.Dijkstra{}, &algorithms.HPAStar{}} {
+		algorithm.Init(30, 20)
+		algorithm.SetStart(2, 10)
+		algorithm.SetEnd(27, 10)
+		if err := algorithm.AddWaypoint(15, 2); err != nil {
+			t.Fatalf("AddWaypoint failed: %v", err)
+		}
+		algorithm.AddWaypoint(15, 17)
+		algorithm.AddWaypoint(5, 5)
+		if err := algorithm.RemoveWaypoint(5, 5); err != nil {
+			t.Fatalf("RemoveWaypoint failed: %v", err)
+		}
+
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T: FindPath failed: %v", algorithm, err)
+		}
+		steps := pathLength(t, algorithm)
+		path, _ := algorithm.GetPath()
+		first, second := -1, -1
+		for i, node := range path {
+			if node.X == 15 && node.Y == 2 && first < 0 {
+				first = i
+			}
+			if node.X == 15 && node.Y == 17 {
+				second = i
+			}
+		}
+		if first < 0 || second < first {
+			t.Fatalf("%T: waypoints were not visited in order", algorithm)
+		}
+		// 13+8 to the first waypoint, 15 down to the second, 12+7 to the end
+		if steps < 55 {
+			t.Fatalf("%T: route of %d steps is shorter than possible", algorithm, steps)
+		}
+
+		lastLeg := 0
+		for {
+			snapshot, err := algorithm.GetSnapshot()
+			if err != nil {
+				t.Fatalf("%T: GetSnapshot failed: %v", algorithm, err)
+			}
+			if snapshot == nil {
+				break
+			}
+			if snapshot.Leg < lastLeg {
+				t.Fatalf("%T: snapshots of leg %d came after leg %d", algorithm, snapshot.Leg, lastLeg)
+			}
+			lastLeg = snapshot.Leg
+		}
+		if lastLeg != 2 {
+			t.Fatalf("%T: expected snapshots for 3 legs, last leg was %d", algorithm, lastLeg)
+		}
+	}
+}
+
+func TestWaypointRouteRevisitsCellsInOrder(t *testing.T) {
+	for _, algorithm := range []algorithms.PathfindingAlgorithm{&algorithms.AStar{}, &algorithms.Dijkstra{}, &algorithms.HPAStar{}} {
+		algorithm.Init(12, 12)
+		algorithm.SetStart(2, 8)
+		algorithm.SetEnd(9, 8)
+		// A dead-end pocket above (5,8), the route has to come back out the way it went in
+		for y := 1; y < 8; y++ {
+			algorithm.SetWall(4, y, true)
+			algorithm.SetWall(6, y, true)
+		}
+		if err := algorithm.AddWaypoint(5, 2); err != nil {
+			t.Fatalf("AddWaypoint failed: %v", err)
+		}
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T: FindPath failed: %v", algorithm, err)
+		}
+
+		path, _ := algorithm.GetPath()
+		for i := 1; i < len(path); i++ {
+			dx, dy := path[i].X-path[i-1].X, path[i].Y-path[i-1].Y
+			if step := dx*dx + dy*dy; step < 1 || step > 2 {
+				t.Fatalf("%T: step %d jumps from (%d,%d) to (%d,%d)", algorithm, i, path[i-1].X, path[i-1].Y, path[i].X, path[i].Y)
+			}
+		}
+		seen := make(map[[2]int]int)
+		for _, node := range path {
+			seen[[2]int{node.X, node.Y}]++
+		}
+		if seen[[2]int{5, 2}] != 1 || seen[[2]int{5, 5}] != 2 {
+			t.Fatalf("%T: expected the route to reach the waypoint once and pass the pocket twice, got %v", algorithm, path)
+		}
+		if last := path[len(path)-1]; last.X != 9 || last.Y != 8 {
+			t.Fatalf("%T: route ends at (%d,%d), expected the end", algorithm, last.X, last.Y)
+		}
+	}
+}
+
+func TestSearchDropsRecordedFrames(t *testing.T) {
+	replay := func(algorithm algorithms.PathfindingAlgorithm, recorded int) int {
+		algorithm.Init(20, 15)
+		algorithm.SetStart(2, 7)
+		algorithm.SetEnd(17, 7)
+		for i := 0; i < recorded; i++ {
+			if err := algorithm.(algorithms.SnapshotRecorder).Record(); err != nil {
+				t.Fatalf("Record failed: %v", err)
+			}
+		}
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T: FindPath failed: %v", algorithm, err)
+		}
+		frames := 0
+		for {
+			snapshot, err := algorithm.GetSnapshot()
+			if err != nil {
+				t.Fatalf("%T: GetSnapshot failed: %v", algorithm, err)
+			}
+			if snapshot == nil {
+				return frames
+			}
+			frames++
+		}
+	}
+	for _, algorithm := range []algorithms.PathfindingAlgorithm{&algorithms.AStar{}, &algorithms.Dijkstra{}, &algorithms.HPAStar{}} {
+		// Frames of a maze generation are queued before the search
+		if searched, withMaze := replay(algorithm, 0), replay(algorithm, 90); withMaze != searched {
+			t.Fatalf("%T: replayed %d frames after recording, %d without", algorithm, withMaze, searched)
+		}
+	}
+}
+
+func TestSolveTourVisitsEveryGoal(t *testing.T) {
+	grid, _ := models.NewGrid(30, 30)
+	for y := 1; y < 25; y++ {
+		grid.SetWall(12, y, true)
+	}
+	var goals []*models.Node
+	for i := 0; i < 16; i++ {
+		node, _ := grid.GetNode(2+(i*7)%26, 2+(i*11)%26)
+		if node.IsWall || node.IsStart || node.IsEnd {
+			continue
+		}
+		goals = append(goals, node)
+	}
+
+	for _, count := range []int{5, len(goals)} {
+		tour, err := algorithms.SolveTour(grid, goals[:count])
+		if err != nil {
+			t.Fatalf("SolveTour failed: %v", err)
+		}
+		seen := make(map[int]bool)
+		for _, goal := range tour.Order {
+			seen[goal] = true
+		}
+		if len(seen) != count || len(tour.Order) != count {
+			t.Fatalf("Tour order %v does not visit all %d goals once", tour.Order, count)
+		}
+		if float64(len(tour.Path)-1) != tour.Cost {
+			t.Fatalf("Tour path has %d steps but costs %v", len(tour.Path)-1, tour.Cost)
+		}
+		for i := 1; i < len(tour.Path); i++ {
+			dx, dy := tour.Path[i].X-tour.Path[i-1].X, tour.Path[i].Y-tour.Path[i-1].Y
+			if dx*dx+dy*dy != 1 || tour.Path[i].IsWall {
+				t.Fatalf("Tour path is broken between %v and %v", tour.Path[i-1], tour.Path[i])
+			}
+		}
+	}
+}
+
+func TestSolveTourIsOptimalForSmallSets(t *testing.T) {
+	grid, _ := models.NewGrid(20, 20)
+	start, _ := grid.GetStart()
+	var goals []*models.Node
+	for _, p := range [][2]int{{2, 2}, {17, 3}, {9, 15}, {3, 17}, {16, 16}, {10, 4}} {
+		node, _ := grid.GetNode(p[0], p[1])
+		goals = append(goals, node)
+	}
+	tour, err := algorithms.SolveTour(grid, goals)
+	if err != nil {
+		t.Fatalf("SolveTour failed: %v", err)
+	}
+
+	// On an open grid the distances are Manhattan, so every order can be checked by brute force
+	manhattan := func(a, b *models.Node) float64 {
+		dx, dy := a.X-b.X, a.Y-b.Y
+		return float64(max(dx, -dx) + max(dy, -dy))
+	}
+	best := -1.0
+	var permute func(order []int, used []bool)
+	permute = func(order []int, used []bool) {
+		if len(order) == len(goals) {
+			cost, previous := 0.0, start
+			for _, i := range order {
+				cost += manhattan(previous, goals[i])
+				previous = goals[i]
+			}
+			if best < 0 || cost < best {
+				best = cost
+			}
+			return
+		}
+		for i := range goals {
+			if !used[i] {
+				used[i] = true
+				permute(append(order, i), used)
+				used[i] = false
+			}
+		}
+	}
+	permute(nil, make([]bool, len(goals)))
+	if tour.Cost != best {
+		t.Fatalf("Tour costs %v, the optimum is %v", tour.Cost, best)
+	}
+}
+
+func TestSearchStopsAtClosestEnd(t *testing.T) {
+	for _, algorithm := range []algorithms.PathfindingAlgorithm{&algorithms.AStar{}, &algorithms.Dijkstra{}, &algorithms.HPAStar{}} {
+		algorithm.Init(40, 30)
+		algorithm.SetStart(20, 15)
+		algorithm.SetEnd(38, 15)
+		if err := algorithm.AddEnd(20, 2); err != nil {
+			t.Fatalf("AddEnd failed: %v", err)
+		}
+		if err := algorithm.AddEnd(38, 15); err == nil {
+			t.Fatalf("%T: adding an existing end succeeded", algorithm)
+		}
+		algorithm.AddEnd(1, 28)
+		if err := algorithm.RemoveEnd(1, 28); err != nil {
+			t.Fatalf("RemoveEnd failed: %v", err)
+		}
+		if ends, _ := algorithm.GetEnds(); len(ends) != 2 {
+			t.Fatalf("%T: expected 2 ends, got %d", algorithm, len(ends))
+		}
+
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T: FindPath failed: %v", algorithm, err)
+		}
+		path, _ := algorithm.GetPath()
+		if last := path[len(path)-1]; last.X != 20 || last.Y != 2 {
+			t.Fatalf("%T: path finished at (%d,%d) instead of the closest end", algorithm, last.X, last.Y)
+		}
+		if len(path)-1 != 13 {
+			t.Fatalf("%T: expected 13 steps to the closest end, got %d", algorithm, len(path)-1)
+		}
+	}
+}
+
+func TestPursuitCatchesMovingTarget(t *testing.T) {
+	grid, _ := models.NewGrid(30, 20)
+	for y := 3; y < 17; y++ {
+		grid.SetWall(15, y, true)
+	}
+	end, _ := grid.GetEnd()
+	corner, _ := grid.GetNode(28, 1)
+	scripted, err := algorithms.NewScriptedTarget(grid, []*models.Node{end, corner})
+	if err != nil {
+		t.Fatalf("NewScriptedTarget failed: %v", err)
+	}
+	policies := []algorithms.TargetPolicy{scripted, algorithms.NewRandomWalkTarget(1), algorithms.EvasiveTarget{}}
+
+	for _, policy := range policies {
+		pursuit, err := algorithms.NewPursuit(grid, policy, 2)
+		if err != nil {
+			t.Fatalf("NewPursuit failed: %v", err)
+		}
+		var snapshot *algorithms.PursuitSnapshot
+		for tick := 0; !pursuit.IsCaught(); tick++ {
+			if tick > 600 {
+				t.Fatalf("%T: target was not caught", policy)
+			}
+			if snapshot, err = pursuit.Tick(); err != nil {
+				t.Fatalf("%T: Tick failed: %v", policy, err)
+			}
+			// The plan always leads from the hunter to the target
+			if !snapshot.Caught {
+				first, last := snapshot.Path[0], snapshot.Path[len(snapshot.Path)-1]
+				if !snapshot.Nodes[first.Y][first.X].IsStart || !snapshot.Nodes[last.Y][last.X].IsEnd {
+					t.Fatalf("%T: plan does not connect the hunter to the target", policy)
+				}
+			}
+		}
+		if _, err := pursuit.Tick(); err == nil {
+			t.Fatalf("%T: ticking after the catch succeeded", policy)
+		}
+	}
+	// The chase works on a copy, the grid itself is untouched
+	if start, _ := grid.GetStart(); !start.IsStart || start.Visited {
+		t.Fatalf("Pursuit modified the grid")
+	}
+}
+
+func TestWeightedNodesAreAvoided(t *testing.T) {
+	for _, algorithm := range []algorithms.PathfindingAlgorithm{&algorithms.AStar{}, &algorithms.Dijkstra{}, &algorithms.HPAStar{}} {
+		algorithm.Init(20, 20)
+		algorithm.SetStart(2, 10)
+		algorithm.SetEnd(17, 10)
+		grid, _ := algorithm.GetGrid()
+		// A costly swamp across the middle, passable in a detour at the top
+		for y := 2; y < 20; y++ {
+			grid.SetWeight(10, y, 50)
+		}
+		if invalidator, ok := algorithm.(algorithms.GridInvalidator); ok {
+			invalidator.Invalidate()
+		}
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T: FindPath failed: %v", algorithm, err)
+		}
+		path, _ := algorithm.GetPath()
+		for _, node := range path {
+			if node.Weight > 1 {
+				t.Fatalf("%T: path crosses the weighted node %v", algorithm, node)
+			}
+		}
+	}
+}
+
+func TestTieBreakingChangesExpansions(t *testing.T) {
+	expanded := map[datastructures.TieBreak]int{}
+	for _, tieBreak := range []datastructures.TieBreak{datastructures.AnyTie, datastructures.LargerG, datastructures.SmallerH,
+		datastructures.FirstIn, datastructures.LastIn, datastructures.RandomTie} {
+		algorithm := &algorithms.AStar{}
+		algorithm.Init(25, 25)
+		algorithm.SetStart(2, 2)
+		algorithm.SetEnd(22, 18)
+		if err := algorithm.SetTieBreak(tieBreak, 3); err != nil {
+			t.Fatalf("SetTieBreak(%d) failed: %v", tieBreak, err)
+		}
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("FindPath with tie break %d failed: %v", tieBreak, err)
+		}
+		expanded[tieBreak] = countVisited(t, algorithm)
+	}
+	// Preferring the deepest node walks straight across the open plateau, breadth first floods it
+	if expanded[datastructures.LargerG] >= expanded[datastructures.FirstIn] {
+		t.Fatalf("Larger g expanded %d nodes, first in %d", expanded[datastructures.LargerG], expanded[datastructures.FirstIn])
+	}
+	if err := (&algorithms.AStar{}).SetTieBreak(datastructures.RandomTie+1, 0); err == nil {
+		t.Fatalf("Unknown tie break was accepted")
+	}
+}
+
+func TestOpenSetsFindEquallyCheapPaths(t *testing.T) {
+	pathCost := func(algorithm algorithms.PathfindingAlgorithm, id datastructures.OpenSetID) int {
+		algorithm.Init(30, 30)
+		algorithm.SetStart(2, 3)
+		algorithm.SetEnd(26, 25)
+		grid, _ := algorithm.GetGrid()
+		for y := 1; y < 29; y++ {
+			for x := 1; x < 29; x++ {
+				grid.SetWeight(x, y, 1+(x*7+y*13)%5)
+			}
+		}
+		if err := algorithm.(algorithms.OpenSetAlgorithm).SetOpenSet(id); err != nil {
+			t.Fatalf("SetOpenSet(%d) failed: %v", id, err)
+		}
+		if err := algorithm.FindPath(); err != nil {
+			t.Fatalf("%T with open set %d: FindPath failed: %v", algorithm, id, err)
+		}
+		path, _ := algorithm.GetPath()
+		cost := 0
+		for i := 1; i < len(path); i++ {
+			cost += int(path[i-1].Cost() + path[i].Cost())
+		}
+		return cost
+	}
+	for _, newAlgorithm := range []func() algorithms.PathfindingAlgorithm{
+		func() algorithms.PathfindingAlgorithm { return &algorithms.Dijkstra{} },
+		func() algorithms.PathfindingAlgorithm { return &algorithms.AStar{} },
+	} {
+		want := pathCost(newAlgorithm(), datastructures.BinaryHeap)
+		for _, id := range []datastructures.OpenSetID{datastructures.Buckets, datastructures.Radix} {
+			if got := pathCost(newAlgorithm(), id); got != want {
+				t.Fatalf("%T with open set %d: path costs %d half steps, binary heap %d", newAlgorithm(), id, got, want)
+			}
+		}
+	}
+}
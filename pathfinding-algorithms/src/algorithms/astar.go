@@ -1,7 +1,6 @@
 package algorithms
 
 import (
-	"container/heap"
 	"errors"
 	"math"
 	"pathfinding-algorithms/datastructures"
@@ -12,12 +11,16 @@ import (
 type AStar struct {
 	grid      *models.Grid
 	solved    bool
-	openSet   *datastructures.PriorityQueue
-	snapshots *datastructures.Queue
-	path      map[models.Node]models.Node
+	openSet   datastructures.OpenSet[*models.Node]
+	snapshots *datastructures.Queue[models.Snapshot]
+	path      []models.Node
 	closedSet map[*models.Node]bool
 	fScore    map[*models.Node]float64
 	gScore    map[*models.Node]float64
+	landmarks Landmarks
+	tieBreak  datastructures.TieBreak
+	tieSeed   int64
+	openSetID datastructures.OpenSetID
 	mu        sync.Mutex
 }
 
@@ -45,14 +48,109 @@ func (a *AStar) Clear() error {
 }
 
 func (a *AStar) resetDataStructures() {
-	a.openSet = &datastructures.PriorityQueue{}
-	a.openSet.Init()
+	a.resetSearch()
+	a.snapshots = &datastructures.Queue[models.Snapshot]{}
+	a.solved = false
+	a.path = nil
+	a.landmarks.Invalidate()
+}
+
+// resetSearch prepares the open and closed sets for searching a new leg
+func (a *AStar) resetSearch() {
+	a.openSet = newOpenSet(a.openSetID, a.tieBreak, a.tieSeed)
 	a.closedSet = make(map[*models.Node]bool)
 	a.fScore = make(map[*models.Node]float64)
 	a.gScore = make(map[*models.Node]float64)
-	a.snapshots = &datastructures.Queue{}
-	a.solved = false
-	a.path = make(map[models.Node]models.Node)
+}
+
+// SetOpenSet selects the data structure holding the open nodes, it applies from the next search
+func (a *AStar) SetOpenSet(id datastructures.OpenSetID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := datastructures.NewOpenSet[*models.Node](id); err != nil {
+		return err
+	}
+	a.openSetID = id
+	return nil
+}
+
+// SetTieBreak selects how open nodes with equal priority are ordered, it applies from the next search
+func (a *AStar) SetTieBreak(tieBreak datastructures.TieBreak, seed int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := (&datastructures.PriorityQueue[*models.Node]{}).SetTieBreak(tieBreak, seed); err != nil {
+		return err
+	}
+	a.tieBreak, a.tieSeed = tieBreak, seed
+	return nil
+}
+
+// Invalidate marks the landmark distance tables as outdated after the grid was modified directly
+func (a *AStar) Invalidate() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	a.landmarks.Invalidate()
+	return nil
+}
+
+// SelectLandmarks enables the ALT heuristic with count landmarks, 0 disables it
+func (a *AStar) SelectLandmarks(count int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	return a.landmarks.SetCount(count)
+}
+
+// AddLandmark picks a landmark at the given location
+func (a *AStar) AddLandmark(x, y int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	return a.landmarks.Add(a.grid, x, y)
+}
+
+// ClearLandmarks disables the ALT heuristic
+func (a *AStar) ClearLandmarks() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	a.landmarks.Clear()
+	return nil
+}
+
+// GetLandmarks returns the landmark cells, computing them if needed
+func (a *AStar) GetLandmarks() ([]*models.Node, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	if err := a.landmarks.Precompute(a.grid); err != nil {
+		return nil, err
+	}
+	return a.landmarks.GetNodes(), nil
+}
+
+// estimate is the heuristic used by the search, the distance to the closest target. The landmark bound is
+// only used if it is tighter than the straight line.
+func (a *AStar) estimate(node *models.Node, targets []*models.Node) float64 {
+	if !a.landmarks.IsEnabled() {
+		return nearestHeuristic(node, targets)
+	}
+	estimate := math.Inf(1)
+	for _, target := range targets {
+		estimate = math.Min(estimate, math.Max(heuristic(node, target), a.landmarks.Estimate(node, target)))
+	}
+	return estimate
 }
 
 // FindPath implements the pathfinding algorithm
@@ -65,6 +163,20 @@ func (a *AStar) FindPath() error {
 	if a.solved {
 		return errors.New("grid is solved")
 	}
+	if err := a.landmarks.Precompute(a.grid); err != nil {
+		return err
+	}
+	route, err := searchLegs(a.grid, a.snapshots, a.findLeg)
+	a.solved = true
+	if err != nil {
+		return err
+	}
+	a.path = route
+	return nil
+}
+
+// findLeg searches a single leg of the route from a stop to the closest of the targets
+func (a *AStar) findLeg(leg int, startNode *models.Node, targets []*models.Node) ([]*models.Node, error) {
 	// Pseudocode:
 	// 1. Initialize open and closed lists
 	// 2. Add the start node to the open list
@@ -78,36 +190,29 @@ func (a *AStar) FindPath() error {
 	//           - Set parent of neighbor to current
 	//           - If neighbor not in open list, add it
 	// 4. Once the end node is reached, backtrack from the end node to start node to get the path
-	startNode, err := a.grid.GetStart()
-	if err != nil {
-		return err
-	}
-	endNode, err := a.grid.GetEnd()
-	if err != nil {
-		return err
-	}
-	heap.Push(a.openSet, datastructures.NewItem(startNode, 0))
+	a.resetSearch()
+	parents := make(map[*models.Node]*models.Node)
+	a.openSet.Insert(datastructures.NewItem(startNode, 0))
 	a.gScore[startNode] = 0
-	a.fScore[startNode] = heuristic(startNode, endNode)
+	a.fScore[startNode] = a.estimate(startNode, targets)
 
 	for a.openSet.Len() > 0 {
-		current := heap.Pop(a.openSet).(*datastructures.Item).GetNode()
+		current := a.openSet.PopMin().GetValue()
 		if current == nil {
-			return errors.New("current is nil")
+			return nil, errors.New("current is nil")
 		}
-		if current.IsEnd {
-			a.solved = true
-			return nil
+		if isTarget(targets, current) {
+			return tracePath(parents, startNode, current), nil
 		}
 
 		a.closedSet[current] = true
-		if !current.IsStart && !current.IsEnd {
+		if !isStop(current) {
 			current.Visited = true
 		}
 
 		neighbors, err := a.grid.GetNeighbors(current)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, neighbor := range neighbors {
 			if a.closedSet[neighbor] || neighbor.IsWall {
@@ -115,36 +220,28 @@ func (a *AStar) FindPath() error {
 			}
 
 			tentativeGScore := a.gScore[current] + distBetween(current, neighbor)
-			//if tentativeGScore >= a.gScore[neighbor] && a.openSet.Contains(neighbor) {
-			//	continue
-			//}
-			if tentativeGScore >= a.gScore[neighbor] && a.gScore[neighbor] != 0 {
+			if g, exists := a.gScore[neighbor]; exists && tentativeGScore >= g {
 				continue
 			}
 
 			// This path is the best until now. Record it!
-			a.path[*neighbor] = *current
+			parents[neighbor] = current
 			a.gScore[neighbor] = tentativeGScore
-			a.fScore[neighbor] = a.gScore[neighbor] + heuristic(neighbor, endNode)
+			h := a.estimate(neighbor, targets)
+			a.fScore[neighbor] = a.gScore[neighbor] + h
 			if !a.openSet.Contains(neighbor) {
-				heap.Push(a.openSet, datastructures.NewItem(neighbor, a.fScore[neighbor]))
+				a.openSet.Insert(datastructures.NewScoredItem(neighbor, a.fScore[neighbor], a.gScore[neighbor], h))
 			} else {
-				a.openSet.Update(neighbor, a.fScore[neighbor])
+				a.openSet.UpdateScored(neighbor, a.fScore[neighbor], a.gScore[neighbor], h)
 			}
 		}
-		snapshot, err := a.grid.DeepCopy()
+		snapshot, err := takeSnapshot(a.grid, leg)
 		if err != nil {
-			return err
-		}
-		if nodes, err := snapshot.GetNodes(); err != nil {
-			return err
-		} else {
-			a.snapshots.Enqueue(nodes)
+			return nil, err
 		}
+		a.snapshots.Enqueue(snapshot)
 	}
-	a.solved = true
-	return errors.New("solution not found")
-
+	return nil, errors.New("solution not found")
 }
 
 func (a *AStar) GetGrid() (*models.Grid, error) {
@@ -156,23 +253,26 @@ func (a *AStar) GetGrid() (*models.Grid, error) {
 	return a.grid, nil
 }
 
-func (a *AStar) GetSnapshot() ([][]*models.Node, error) {
+func (a *AStar) GetSnapshot() (*models.Snapshot, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.grid == nil {
 		return nil, errors.New("grid is nil")
 	}
-	if !a.solved {
+	if !a.solved && a.snapshots.IsEmpty() {
 		return nil, errors.New("astar is not solved")
 	}
-	if a.snapshots.IsEmpty() {
-		return nil, nil
-	} else {
-		return a.snapshots.Dequeue().([][]*models.Node), nil
-	}
+	return nextSnapshot(a.snapshots), nil
 }
 
-func (a *AStar) GetPath() (map[models.Node]models.Node, error) {
+// Record implements SnapshotRecorder
+func (a *AStar) Record() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return recordSnapshot(a.grid, a.snapshots)
+}
+
+func (a *AStar) GetPath() ([]models.Node, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	if a.grid == nil {
@@ -226,6 +326,72 @@ func (a *AStar) GetEnd() (*models.Node, error) {
 	return a.grid.GetEnd()
 }
 
+func (a *AStar) AddWaypoint(x, y int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if a.solved {
+		return errors.New("grid is solved")
+	}
+	return a.grid.AddWaypoint(x, y)
+}
+
+func (a *AStar) RemoveWaypoint(x, y int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if a.solved {
+		return errors.New("grid is solved")
+	}
+	return a.grid.RemoveWaypoint(x, y)
+}
+
+func (a *AStar) GetWaypoints() ([]*models.Node, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return a.grid.GetWaypoints()
+}
+
+func (a *AStar) AddEnd(x, y int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if a.solved {
+		return errors.New("grid is solved")
+	}
+	return a.grid.AddEnd(x, y)
+}
+
+func (a *AStar) RemoveEnd(x, y int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if a.solved {
+		return errors.New("grid is solved")
+	}
+	return a.grid.RemoveEnd(x, y)
+}
+
+func (a *AStar) GetEnds() ([]*models.Node, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return a.grid.GetEnds()
+}
+
 func (a *AStar) SetWall(x, y int, isWall bool) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -235,7 +401,11 @@ func (a *AStar) SetWall(x, y int, isWall bool) error {
 	if a.solved {
 		return errors.New("grid is solved")
 	}
-	return a.grid.SetWall(x, y, isWall)
+	if err := a.grid.SetWall(x, y, isWall); err != nil {
+		return err
+	}
+	a.landmarks.Invalidate()
+	return nil
 }
 
 func heuristic(a, b *models.Node) float64 {
@@ -244,13 +414,16 @@ func heuristic(a, b *models.Node) float64 {
 	return math.Sqrt(dx*dx + dy*dy)
 }
 
+// distBetween is the cost of a step between two nodes. Each node weighs in for half the step, which keeps the
+// cost the same in both directions, as the searches backwards from the end rely on.
 func distBetween(current, neighbor *models.Node) float64 {
 	dx := math.Abs(float64(current.X - neighbor.X))
 	dy := math.Abs(float64(current.Y - neighbor.Y))
+	weight := (current.Cost() + neighbor.Cost()) / 2
 	if dx == 1 && dy == 1 {
 		// Diagonal movement
-		return 1.414 // sqrt(2), assuming diagonal cost is sqrt(2) times an orthogonal step
+		return 1.414 * weight // sqrt(2), assuming diagonal cost is sqrt(2) times an orthogonal step
 	}
 	// Orthogonal movement
-	return 1
+	return weight
 }
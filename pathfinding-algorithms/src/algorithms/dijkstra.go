@@ -1,7 +1,6 @@
 package algorithms
 
 import (
-	"container/heap"
 	"errors"
 	"pathfinding-algorithms/datastructures"
 	"pathfinding-algorithms/models"
@@ -12,10 +11,14 @@ import (
 type Dijkstra struct {
 	grid      *models.Grid
 	solved    bool
-	openSet   *datastructures.PriorityQueue
-	snapshots *datastructures.Queue
-	path      map[models.Node]models.Node
+	openSet   datastructures.OpenSet[*models.Node]
+	snapshots *datastructures.Queue[models.Snapshot]
+	path      []models.Node
 	distances map[*models.Node]float64
+	closedSet map[*models.Node]bool
+	tieBreak  datastructures.TieBreak
+	tieSeed   int64
+	openSetID datastructures.OpenSetID
 	mu        sync.Mutex
 }
 
@@ -47,11 +50,38 @@ func (d *Dijkstra) Clear() error {
 
 func (d *Dijkstra) resetDataStructures() {
 	d.solved = false
-	d.openSet = &datastructures.PriorityQueue{}
-	d.openSet.Init()
+	d.resetSearch()
+	d.snapshots = &datastructures.Queue[models.Snapshot]{}
+	d.path = nil
+}
+
+// resetSearch prepares the open set and distances for searching a new leg
+func (d *Dijkstra) resetSearch() {
+	d.openSet = newOpenSet(d.openSetID, d.tieBreak, d.tieSeed)
 	d.distances = make(map[*models.Node]float64)
-	d.snapshots = &datastructures.Queue{}
-	d.path = make(map[models.Node]models.Node)
+	d.closedSet = make(map[*models.Node]bool)
+}
+
+// SetOpenSet selects the data structure holding the open nodes, it applies from the next search
+func (d *Dijkstra) SetOpenSet(id datastructures.OpenSetID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := datastructures.NewOpenSet[*models.Node](id); err != nil {
+		return err
+	}
+	d.openSetID = id
+	return nil
+}
+
+// SetTieBreak selects how open nodes with equal priority are ordered, it applies from the next search
+func (d *Dijkstra) SetTieBreak(tieBreak datastructures.TieBreak, seed int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := (&datastructures.PriorityQueue[*models.Node]{}).SetTieBreak(tieBreak, seed); err != nil {
+		return err
+	}
+	d.tieBreak, d.tieSeed = tieBreak, seed
+	return nil
 }
 
 // FindPath implements Dijkstra's algorithm for finding the shortest path
@@ -65,59 +95,64 @@ func (d *Dijkstra) FindPath() error {
 	if d.solved {
 		return errors.New("grid is solved")
 	}
-
-	startNode, err := d.grid.GetStart()
+	route, err := searchLegs(d.grid, d.snapshots, d.findLeg)
+	d.solved = true
 	if err != nil {
 		return err
 	}
+	d.path = route
+	return nil
+}
+
+// findLeg searches a single leg of the route from a stop to the closest of the targets
+func (d *Dijkstra) findLeg(leg int, startNode *models.Node, targets []*models.Node) ([]*models.Node, error) {
+	d.resetSearch()
+	parents := make(map[*models.Node]*models.Node)
 	d.distances[startNode] = 0
-	heap.Push(d.openSet, datastructures.NewItem(startNode, 0))
+	d.openSet.Insert(datastructures.NewItem(startNode, 0))
 
 	for d.openSet.Len() > 0 {
-		current := heap.Pop(d.openSet).(*datastructures.Item).GetNode()
+		current := d.openSet.PopMin().GetValue()
 
-		if current.IsEnd {
-			d.solved = true
-			return nil
+		if isTarget(targets, current) {
+			return tracePath(parents, startNode, current), nil
 		}
 
-		if !current.IsStart && !current.IsEnd {
+		d.closedSet[current] = true
+		if !isStop(current) {
 			current.Visited = true
 		}
 
 		neighbors, err := d.grid.GetNeighbors(current)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, neighbor := range neighbors {
-			if neighbor.Visited || neighbor.IsWall {
+			if d.closedSet[neighbor] || neighbor.IsWall {
 				continue
 			}
 
 			tentativeDistance := d.distances[current] + distBetween(current, neighbor)
 			if dist, exists := d.distances[neighbor]; !exists || tentativeDistance < dist {
 				d.distances[neighbor] = tentativeDistance
-				d.path[*neighbor] = *current
+				parents[neighbor] = current
+				// The estimate does not steer the search, it only orders nodes at the same distance
+				h := nearestHeuristic(neighbor, targets)
 				if !d.openSet.Contains(neighbor) {
-					heap.Push(d.openSet, datastructures.NewItem(neighbor, tentativeDistance))
+					d.openSet.Insert(datastructures.NewScoredItem(neighbor, tentativeDistance, tentativeDistance, h))
 				} else {
-					d.openSet.Update(neighbor, tentativeDistance)
+					d.openSet.UpdateScored(neighbor, tentativeDistance, tentativeDistance, h)
 				}
 			}
 		}
 
-		snapshot, err := d.grid.DeepCopy()
+		snapshot, err := takeSnapshot(d.grid, leg)
 		if err != nil {
-			return err
-		}
-		if nodes, err := snapshot.GetNodes(); err != nil {
-			return err
-		} else {
-			d.snapshots.Enqueue(nodes)
+			return nil, err
 		}
+		d.snapshots.Enqueue(snapshot)
 	}
-	d.solved = true
-	return errors.New("path to destination not found")
+	return nil, errors.New("path to destination not found")
 }
 
 func (d *Dijkstra) GetGrid() (*models.Grid, error) {
@@ -129,23 +164,26 @@ func (d *Dijkstra) GetGrid() (*models.Grid, error) {
 	return d.grid, nil
 }
 
-func (d *Dijkstra) GetSnapshot() ([][]*models.Node, error) {
+func (d *Dijkstra) GetSnapshot() (*models.Snapshot, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	if d.grid == nil {
 		return nil, errors.New("grid is nil")
 	}
-	if !d.solved {
+	if !d.solved && d.snapshots.IsEmpty() {
 		return nil, errors.New("astar is not solved")
 	}
-	if d.snapshots.IsEmpty() {
-		return nil, nil
-	} else {
-		return d.snapshots.Dequeue().([][]*models.Node), nil
-	}
+	return nextSnapshot(d.snapshots), nil
 }
 
-func (d *Dijkstra) GetPath() (map[models.Node]models.Node, error) {
+// Record implements SnapshotRecorder
+func (d *Dijkstra) Record() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return recordSnapshot(d.grid, d.snapshots)
+}
+
+func (d *Dijkstra) GetPath() ([]models.Node, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	if d.grid == nil {
@@ -199,6 +237,72 @@ func (d *Dijkstra) GetEnd() (*models.Node, error) {
 	return d.grid.GetEnd()
 }
 
+func (d *Dijkstra) AddWaypoint(x, y int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if d.solved {
+		return errors.New("grid is solved")
+	}
+	return d.grid.AddWaypoint(x, y)
+}
+
+func (d *Dijkstra) RemoveWaypoint(x, y int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if d.solved {
+		return errors.New("grid is solved")
+	}
+	return d.grid.RemoveWaypoint(x, y)
+}
+
+func (d *Dijkstra) GetWaypoints() ([]*models.Node, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return d.grid.GetWaypoints()
+}
+
+func (d *Dijkstra) AddEnd(x, y int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if d.solved {
+		return errors.New("grid is solved")
+	}
+	return d.grid.AddEnd(x, y)
+}
+
+func (d *Dijkstra) RemoveEnd(x, y int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if d.solved {
+		return errors.New("grid is solved")
+	}
+	return d.grid.RemoveEnd(x, y)
+}
+
+func (d *Dijkstra) GetEnds() ([]*models.Node, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return d.grid.GetEnds()
+}
+
 func (d *Dijkstra) SetWall(x, y int, isWall bool) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -0,0 +1,55 @@
+package algorithms
+
+import (
+	"errors"
+	"pathfinding-algorithms/models"
+)
+
+// FlowField stores, for every cell, the cost of reaching the closest end node and the direction to step in
+// to get there. Any number of agents can follow it without searching on their own.
+type FlowField struct {
+	Costs      [][]float64      // Integration field, +Inf for walls and cells that can not reach the end
+	Directions [][]models.Point // Unit step towards the end, zero for the end itself and unreachable cells
+}
+
+// NewFlowField integrates the cost from the end nodes over the whole grid and derives the directions
+func NewFlowField(grid *models.Grid) (*FlowField, error) {
+	if grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	ends, err := grid.GetEnds()
+	if err != nil {
+		return nil, err
+	}
+	// Moving costs the same in both directions, so the distances from the ends are the costs to reach them
+	tree, err := NewShortestPathTree(grid, ends[0], ends[1:]...)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	field := &FlowField{
+		Costs:      make([][]float64, len(nodes)),
+		Directions: make([][]models.Point, len(nodes)),
+	}
+	for y, row := range nodes {
+		field.Costs[y] = make([]float64, len(row))
+		field.Directions[y] = make([]models.Point, len(row))
+		for x, node := range row {
+			field.Costs[y][x] = tree.Distance(node)
+		}
+	}
+
+	// Point every cell at its parent in the tree, the next step of its cheapest path to an end
+	for y, row := range tree.parents {
+		for x, parent := range row {
+			if parent != nil {
+				field.Directions[y][x] = models.Point{Dx: parent.X - x, Dy: parent.Y - y}
+			}
+		}
+	}
+	return field, nil
+}
@@ -0,0 +1,716 @@
+package algorithms
+
+import (
+	"errors"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+	"sync"
+)
+
+const (
+	defaultClusterSize = 10
+	// Entrances wider than this get a transition at both ends instead of a single one in the middle
+	maxEntranceWidth = 6
+)
+
+// HPAStar implements hierarchical pathfinding. The grid is partitioned into clusters, entrances between
+// neighbouring clusters form an abstract graph which is searched first, then refined into a cell path.
+type HPAStar struct {
+	grid      *models.Grid
+	solved    bool
+	clusters  [][]*cluster
+	borders   map[borderKey]*border
+	snapshots *datastructures.Queue[models.Snapshot]
+	path      []models.Node
+	mu        sync.Mutex
+}
+
+// ClusterBounds describes the cells covered by a single cluster
+type ClusterBounds struct {
+	X, Y, Width, Height int
+}
+
+// AbstractEdge connects two entrance nodes of the abstract graph. Inter edges cross a cluster border,
+// intra edges are the shortest paths between two entrances of the same cluster.
+type AbstractEdge struct {
+	From, To models.Node
+	Cost     float64
+	Inter    bool
+}
+
+// Hierarchy is a read-only view of the abstraction used to overlay it on the grid
+type Hierarchy struct {
+	ClusterSize int
+	Clusters    []ClusterBounds
+	Edges       []AbstractEdge
+}
+
+type cluster struct {
+	x, y, width, height int
+	edges               map[*models.Node][]*intraEdge
+}
+
+type intraEdge struct {
+	to   *models.Node
+	cost float64
+	path []*models.Node // Cells from the edge's origin to its destination, both inclusive
+}
+
+// borderKey identifies the border between the cluster at (cx, cy) and its right or bottom neighbour
+type borderKey struct {
+	cx, cy     int
+	horizontal bool
+}
+
+type border struct {
+	// Each pair holds an entrance node on the first cluster's side and its partner on the second
+	pairs [][2]*models.Node
+}
+
+func (h *HPAStar) Init(width, height int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var err error
+	h.grid, err = models.NewGrid(width, height)
+	if err != nil {
+		return err
+	}
+	h.resetDataStructures()
+	return h.buildAbstraction()
+}
+
+func (h *HPAStar) Clear() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	var err error
+	h.grid, err = models.NewGrid(h.grid.GetWidth(), h.grid.GetHeight())
+	if err != nil {
+		return err
+	}
+	h.resetDataStructures()
+	return h.buildAbstraction()
+}
+
+// Invalidate rebuilds the whole abstraction after the grid was modified without SetWall
+func (h *HPAStar) Invalidate() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	return h.buildAbstraction()
+}
+
+func (h *HPAStar) resetDataStructures() {
+	h.solved = false
+	h.snapshots = &datastructures.Queue[models.Snapshot]{}
+	h.path = nil
+}
+
+// buildAbstraction partitions the grid into clusters and computes every entrance and intra-cluster edge
+func (h *HPAStar) buildAbstraction() error {
+	width, height := h.grid.GetWidth(), h.grid.GetHeight()
+	columns := (width + defaultClusterSize - 1) / defaultClusterSize
+	rows := (height + defaultClusterSize - 1) / defaultClusterSize
+
+	h.clusters = make([][]*cluster, rows)
+	for cy := range h.clusters {
+		h.clusters[cy] = make([]*cluster, columns)
+		for cx := range h.clusters[cy] {
+			x, y := cx*defaultClusterSize, cy*defaultClusterSize
+			h.clusters[cy][cx] = &cluster{
+				x:      x,
+				y:      y,
+				width:  min(defaultClusterSize, width-x),
+				height: min(defaultClusterSize, height-y),
+			}
+		}
+	}
+
+	h.borders = make(map[borderKey]*border)
+	for cy := range h.clusters {
+		for cx := range h.clusters[cy] {
+			if err := h.buildBorder(borderKey{cx, cy, true}); err != nil {
+				return err
+			}
+			if err := h.buildBorder(borderKey{cx, cy, false}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, row := range h.clusters {
+		for _, c := range row {
+			if err := h.buildIntraEdges(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// updateAbstraction recomputes the borders of the cluster containing (x, y) and the intra-cluster edges
+// of that cluster and its direct neighbours, leaving the rest of the abstraction untouched
+func (h *HPAStar) updateAbstraction(x, y int) error {
+	cx, cy := x/defaultClusterSize, y/defaultClusterSize
+	keys := []borderKey{
+		{cx, cy, true}, {cx, cy, false},
+		{cx - 1, cy, true}, {cx, cy - 1, false},
+	}
+	for _, key := range keys {
+		if err := h.buildBorder(key); err != nil {
+			return err
+		}
+	}
+	for _, d := range cartesianOffsets {
+		if c := h.clusterAt(cx+d.Dx, cy+d.Dy); c != nil {
+			if err := h.buildIntraEdges(c); err != nil {
+				return err
+			}
+		}
+	}
+	return h.buildIntraEdges(h.clusters[cy][cx])
+}
+
+var cartesianOffsets = []models.Point{{Dx: 0, Dy: -1}, {Dx: -1, Dy: 0}, {Dx: 1, Dy: 0}, {Dx: 0, Dy: 1}}
+
+func (h *HPAStar) clusterAt(cx, cy int) *cluster {
+	if cy < 0 || cy >= len(h.clusters) || cx < 0 || cx >= len(h.clusters[cy]) {
+		return nil
+	}
+	return h.clusters[cy][cx]
+}
+
+func (h *HPAStar) clusterOf(node *models.Node) *cluster {
+	return h.clusters[node.Y/defaultClusterSize][node.X/defaultClusterSize]
+}
+
+// buildBorder finds the entrances between a cluster and its right (horizontal) or bottom neighbour
+func (h *HPAStar) buildBorder(key borderKey) error {
+	a := h.clusterAt(key.cx, key.cy)
+	var b *cluster
+	if key.horizontal {
+		b = h.clusterAt(key.cx+1, key.cy)
+	} else {
+		b = h.clusterAt(key.cx, key.cy+1)
+	}
+	if a == nil || b == nil {
+		return nil
+	}
+
+	length := a.height
+	if !key.horizontal {
+		length = a.width
+	}
+	cellsAt := func(i int) (*models.Node, *models.Node, error) {
+		if key.horizontal {
+			x := a.x + a.width - 1
+			first, err := h.grid.GetNode(x, a.y+i)
+			if err != nil {
+				return nil, nil, err
+			}
+			second, err := h.grid.GetNode(x+1, a.y+i)
+			return first, second, err
+		}
+		y := a.y + a.height - 1
+		first, err := h.grid.GetNode(a.x+i, y)
+		if err != nil {
+			return nil, nil, err
+		}
+		second, err := h.grid.GetNode(a.x+i, y+1)
+		return first, second, err
+	}
+
+	result := &border{}
+	addSegment := func(start, end int) error {
+		positions := []int{(start + end) / 2}
+		if end-start+1 > maxEntranceWidth {
+			positions = []int{start, end}
+		}
+		for _, i := range positions {
+			first, second, err := cellsAt(i)
+			if err != nil {
+				return err
+			}
+			result.pairs = append(result.pairs, [2]*models.Node{first, second})
+		}
+		return nil
+	}
+
+	segmentStart := -1
+	for i := 0; i < length; i++ {
+		first, second, err := cellsAt(i)
+		if err != nil {
+			return err
+		}
+		open := !first.IsWall && !second.IsWall
+		if open && segmentStart < 0 {
+			segmentStart = i
+		} else if !open && segmentStart >= 0 {
+			if err := addSegment(segmentStart, i-1); err != nil {
+				return err
+			}
+			segmentStart = -1
+		}
+	}
+	if segmentStart >= 0 {
+		if err := addSegment(segmentStart, length-1); err != nil {
+			return err
+		}
+	}
+	h.borders[key] = result
+	return nil
+}
+
+// entrancesOf returns every entrance node located inside the given cluster
+func (h *HPAStar) entrancesOf(c *cluster) []*models.Node {
+	cx, cy := c.x/defaultClusterSize, c.y/defaultClusterSize
+	seen := make(map[*models.Node]bool)
+	var entrances []*models.Node
+	add := func(key borderKey, side int) {
+		if b, exists := h.borders[key]; exists {
+			for _, pair := range b.pairs {
+				if !seen[pair[side]] {
+					seen[pair[side]] = true
+					entrances = append(entrances, pair[side])
+				}
+			}
+		}
+	}
+	add(borderKey{cx, cy, true}, 0)
+	add(borderKey{cx, cy, false}, 0)
+	add(borderKey{cx - 1, cy, true}, 1)
+	add(borderKey{cx, cy - 1, false}, 1)
+	return entrances
+}
+
+// buildIntraEdges connects every pair of entrances of a cluster that can reach each other inside it
+func (h *HPAStar) buildIntraEdges(c *cluster) error {
+	c.edges = make(map[*models.Node][]*intraEdge)
+	entrances := h.entrancesOf(c)
+	for _, from := range entrances {
+		distances, parents, err := h.searchCluster(c, from)
+		if err != nil {
+			return err
+		}
+		for _, to := range entrances {
+			if to == from {
+				continue
+			}
+			if cost, reachable := distances[to]; reachable {
+				c.edges[from] = append(c.edges[from], &intraEdge{to: to, cost: cost, path: tracePath(parents, from, to)})
+			}
+		}
+	}
+	return nil
+}
+
+// searchCluster runs Dijkstra from source without leaving the cluster
+func (h *HPAStar) searchCluster(c *cluster, source *models.Node) (map[*models.Node]float64, map[*models.Node]*models.Node, error) {
+	distances := map[*models.Node]float64{source: 0}
+	parents := make(map[*models.Node]*models.Node)
+	closed := make(map[*models.Node]bool)
+	openSet := &datastructures.PriorityQueue[*models.Node]{}
+	openSet.Init()
+	openSet.Insert(datastructures.NewItem(source, 0))
+
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		closed[current] = true
+		neighbors, err := h.grid.GetNeighbors(current)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, neighbor := range neighbors {
+			if closed[neighbor] || neighbor.IsWall || !c.contains(neighbor) {
+				continue
+			}
+			tentativeDistance := distances[current] + distBetween(current, neighbor)
+			if dist, exists := distances[neighbor]; !exists || tentativeDistance < dist {
+				distances[neighbor] = tentativeDistance
+				parents[neighbor] = current
+				if !openSet.Contains(neighbor) {
+					openSet.Insert(datastructures.NewItem(neighbor, tentativeDistance))
+				} else {
+					openSet.Update(neighbor, tentativeDistance)
+				}
+			}
+		}
+	}
+	return distances, parents, nil
+}
+
+func (c *cluster) contains(node *models.Node) bool {
+	return node.X >= c.x && node.X < c.x+c.width && node.Y >= c.y && node.Y < c.y+c.height
+}
+
+// tracePath follows parents back from to until reaching from, returning the cells in travel order
+func tracePath(parents map[*models.Node]*models.Node, from, to *models.Node) []*models.Node {
+	path := []*models.Node{to}
+	for current := to; current != from; {
+		current = parents[current]
+		path = append(path, current)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// FindPath searches the abstract graph for every leg of the route, then refines the result into cells
+func (h *HPAStar) FindPath() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	route, err := searchLegs(h.grid, h.snapshots, h.findLeg)
+	h.solved = true
+	if err != nil {
+		return err
+	}
+	h.path = route
+	return nil
+}
+
+// findLeg searches the abstract graph from a stop to the closest of the targets and refines the abstract path
+func (h *HPAStar) findLeg(leg int, startNode *models.Node, targets []*models.Node) ([]*models.Node, error) {
+	// Temporarily connect start and targets to the entrances of their clusters
+	startEdges, err := h.connectToCluster(startNode, targets)
+	if err != nil {
+		return nil, err
+	}
+	toEnd := make(map[*models.Node][]*intraEdge)
+	for _, target := range targets {
+		endEdges, err := h.connectToCluster(target, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range endEdges {
+			toEnd[edge.to] = append(toEnd[edge.to], &intraEdge{to: target, cost: edge.cost, path: reversed(edge.path)})
+		}
+	}
+
+	openSet := &datastructures.PriorityQueue[*models.Node]{}
+	openSet.Init()
+	gScore := map[*models.Node]float64{startNode: 0}
+	closedSet := make(map[*models.Node]bool)
+	via := make(map[*models.Node]*intraEdge)
+	parents := make(map[*models.Node]*models.Node)
+	openSet.Insert(datastructures.NewItem(startNode, nearestHeuristic(startNode, targets)))
+
+	// Large grids are what the hierarchy is meant for, only every few expansions are recorded there
+	snapshotInterval, expansions := h.grid.SnapshotInterval(), 0
+
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		if isTarget(targets, current) {
+			return h.refine(startNode, current, parents, via), nil
+		}
+		closedSet[current] = true
+		if !isStop(current) {
+			current.Visited = true
+		}
+
+		var edges []*intraEdge
+		if current == startNode {
+			edges = append(edges, startEdges...)
+		} else {
+			edges = append(edges, h.clusterOf(current).edges[current]...)
+		}
+		edges = append(edges, toEnd[current]...)
+		for _, partner := range h.partnersOf(current) {
+			edges = append(edges, &intraEdge{to: partner, cost: distBetween(current, partner), path: []*models.Node{current, partner}})
+		}
+		for _, edge := range edges {
+			if closedSet[edge.to] {
+				continue
+			}
+			tentativeGScore := gScore[current] + edge.cost
+			if g, exists := gScore[edge.to]; exists && tentativeGScore >= g {
+				continue
+			}
+			gScore[edge.to] = tentativeGScore
+			parents[edge.to] = current
+			via[edge.to] = edge
+			fScore := tentativeGScore + nearestHeuristic(edge.to, targets)
+			if !openSet.Contains(edge.to) {
+				openSet.Insert(datastructures.NewItem(edge.to, fScore))
+			} else {
+				openSet.Update(edge.to, fScore)
+			}
+		}
+
+		expansions++
+		if expansions%snapshotInterval != 0 {
+			continue
+		}
+		snapshot, err := takeSnapshot(h.grid, leg)
+		if err != nil {
+			return nil, err
+		}
+		h.snapshots.Enqueue(snapshot)
+	}
+	return nil, errors.New("path to destination not found")
+}
+
+// connectToCluster computes the edges from node to every entrance of its cluster, plus the targets that
+// share the cluster
+func (h *HPAStar) connectToCluster(node *models.Node, targets []*models.Node) ([]*intraEdge, error) {
+	c := h.clusterOf(node)
+	distances, parents, err := h.searchCluster(c, node)
+	if err != nil {
+		return nil, err
+	}
+	destinations := h.entrancesOf(c)
+	for _, target := range targets {
+		if c.contains(target) {
+			destinations = append(destinations, target)
+		}
+	}
+	var edges []*intraEdge
+	for _, destination := range destinations {
+		if cost, reachable := distances[destination]; reachable && destination != node {
+			edges = append(edges, &intraEdge{to: destination, cost: cost, path: tracePath(parents, node, destination)})
+		}
+	}
+	return edges, nil
+}
+
+// partnersOf returns the nodes across a cluster border that node is directly connected to
+func (h *HPAStar) partnersOf(node *models.Node) []*models.Node {
+	cx, cy := node.X/defaultClusterSize, node.Y/defaultClusterSize
+	var partners []*models.Node
+	find := func(key borderKey, side int) {
+		if b, exists := h.borders[key]; exists {
+			for _, pair := range b.pairs {
+				if pair[side] == node {
+					partners = append(partners, pair[1-side])
+				}
+			}
+		}
+	}
+	find(borderKey{cx, cy, true}, 0)
+	find(borderKey{cx, cy, false}, 0)
+	find(borderKey{cx - 1, cy, true}, 1)
+	find(borderKey{cx, cy - 1, false}, 1)
+	return partners
+}
+
+// refine expands the abstract path into the cells travelled from start to end
+func (h *HPAStar) refine(start, end *models.Node, parents map[*models.Node]*models.Node, via map[*models.Node]*intraEdge) []*models.Node {
+	cells := []*models.Node{end}
+	for current := end; current != start; current = parents[current] {
+		edgeCells := via[current].path
+		for i := len(edgeCells) - 2; i >= 0; i-- {
+			cells = append(cells, edgeCells[i])
+		}
+	}
+	return reversed(cells)
+}
+
+func reversed(path []*models.Node) []*models.Node {
+	result := make([]*models.Node, len(path))
+	for i, node := range path {
+		result[len(path)-1-i] = node
+	}
+	return result
+}
+
+// GetHierarchy returns the clusters and abstract edges of the current abstraction
+func (h *HPAStar) GetHierarchy() (*Hierarchy, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	hierarchy := &Hierarchy{ClusterSize: defaultClusterSize}
+	for _, row := range h.clusters {
+		for _, c := range row {
+			hierarchy.Clusters = append(hierarchy.Clusters, ClusterBounds{X: c.x, Y: c.y, Width: c.width, Height: c.height})
+			for from, edges := range c.edges {
+				for _, edge := range edges {
+					// Intra edges are stored in both directions, only report each once
+					if from.Y < edge.to.Y || (from.Y == edge.to.Y && from.X < edge.to.X) {
+						hierarchy.Edges = append(hierarchy.Edges, AbstractEdge{From: *from, To: *edge.to, Cost: edge.cost})
+					}
+				}
+			}
+		}
+	}
+	for _, b := range h.borders {
+		for _, pair := range b.pairs {
+			hierarchy.Edges = append(hierarchy.Edges, AbstractEdge{From: *pair[0], To: *pair[1], Cost: distBetween(pair[0], pair[1]), Inter: true})
+		}
+	}
+	return hierarchy, nil
+}
+
+func (h *HPAStar) GetGrid() (*models.Grid, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return h.grid, nil
+}
+
+func (h *HPAStar) GetSnapshot() (*models.Snapshot, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	if !h.solved && h.snapshots.IsEmpty() {
+		return nil, errors.New("hpastar is not solved")
+	}
+	return nextSnapshot(h.snapshots), nil
+}
+
+// Record implements SnapshotRecorder
+func (h *HPAStar) Record() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return recordSnapshot(h.grid, h.snapshots)
+}
+
+func (h *HPAStar) GetPath() ([]models.Node, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	if !h.solved {
+		return nil, errors.New("grid is not solved")
+	}
+	return h.path, nil
+}
+
+func (h *HPAStar) SetStart(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.SetStart(x, y)
+}
+
+func (h *HPAStar) SetEnd(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.SetEnd(x, y)
+}
+
+func (h *HPAStar) GetStart() (*models.Node, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return h.grid.GetStart()
+}
+
+func (h *HPAStar) GetEnd() (*models.Node, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return h.grid.GetEnd()
+}
+
+func (h *HPAStar) AddWaypoint(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.AddWaypoint(x, y)
+}
+
+func (h *HPAStar) RemoveWaypoint(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.RemoveWaypoint(x, y)
+}
+
+func (h *HPAStar) GetWaypoints() ([]*models.Node, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return h.grid.GetWaypoints()
+}
+
+func (h *HPAStar) AddEnd(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.AddEnd(x, y)
+}
+
+func (h *HPAStar) RemoveEnd(x, y int) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	return h.grid.RemoveEnd(x, y)
+}
+
+func (h *HPAStar) GetEnds() ([]*models.Node, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return h.grid.GetEnds()
+}
+
+// SetWall changes a wall and updates the abstraction around it
+func (h *HPAStar) SetWall(x, y int, isWall bool) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.grid == nil {
+		return errors.New("grid is nil")
+	}
+	if h.solved {
+		return errors.New("grid is solved")
+	}
+	if err := h.grid.SetWall(x, y, isWall); err != nil {
+		return err
+	}
+	return h.updateAbstraction(x, y)
+}
@@ -0,0 +1,166 @@
+package algorithms
+
+import (
+	"errors"
+	"math"
+	"pathfinding-algorithms/models"
+)
+
+// Landmarks provides the ALT (A*, Landmarks, Triangle inequality) heuristic. The exact distance from a
+// few landmarks to every cell is precomputed, and for any landmark L the triangle inequality gives
+// |d(L, target) - d(L, node)| as a lower bound on the distance between node and target.
+type Landmarks struct {
+	count  int            // Total number of landmarks, picked ones included
+	picked []models.Point // Positions chosen by the user, the rest are selected by farthest-point
+	trees  []*ShortestPathTree
+	stale  bool
+}
+
+// SetCount sets how many landmarks are used, positions not picked by the user are filled in by
+// farthest-point selection. Picked landmarks beyond count are dropped, the latest first.
+func (l *Landmarks) SetCount(count int) error {
+	if count < 0 {
+		return errors.New("landmark count must not be negative")
+	}
+	l.count = count
+	l.picked = l.picked[:min(count, len(l.picked))]
+	l.stale = true
+	return nil
+}
+
+// Add picks the landmark at (x, y)
+func (l *Landmarks) Add(grid *models.Grid, x, y int) error {
+	node, err := grid.GetNode(x, y)
+	if err != nil {
+		return err
+	}
+	if node.IsWall {
+		return errors.New("landmark can not be a wall")
+	}
+	for _, p := range l.picked {
+		if p.Dx == x && p.Dy == y {
+			return errors.New("landmark already exists")
+		}
+	}
+	l.picked = append(l.picked, models.Point{Dx: x, Dy: y})
+	l.count = max(l.count, len(l.picked))
+	l.stale = true
+	return nil
+}
+
+// Clear removes every landmark
+func (l *Landmarks) Clear() {
+	l.count = 0
+	l.picked = nil
+	l.trees = nil
+	l.stale = false
+}
+
+// Invalidate marks the distance tables as outdated, they are recomputed on next use
+func (l *Landmarks) Invalidate() {
+	l.stale = true
+}
+
+// IsEnabled reports whether any landmark is configured
+func (l *Landmarks) IsEnabled() bool {
+	return l.count > 0
+}
+
+// Precompute selects the landmarks and computes their distance tables if they are outdated
+func (l *Landmarks) Precompute(grid *models.Grid) error {
+	if !l.stale {
+		return nil
+	}
+	l.trees = nil
+	for _, p := range l.picked {
+		// A picked landmark that is outside a resized grid or has been walled over is skipped, not forgotten
+		node, err := grid.GetNode(p.Dx, p.Dy)
+		if err != nil || node.IsWall {
+			continue
+		}
+		if err := l.addTree(grid, node); err != nil {
+			return err
+		}
+	}
+	for len(l.trees) < l.count {
+		node, err := l.farthestNode(grid)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			break
+		}
+		if err := l.addTree(grid, node); err != nil {
+			return err
+		}
+	}
+	l.stale = false
+	return nil
+}
+
+func (l *Landmarks) addTree(grid *models.Grid, node *models.Node) error {
+	tree, err := NewShortestPathTree(grid, node)
+	if err != nil {
+		return err
+	}
+	l.trees = append(l.trees, tree)
+	return nil
+}
+
+// farthestNode returns the reachable cell maximizing the distance to the closest existing landmark,
+// the first landmark is the cell farthest from the start node
+func (l *Landmarks) farthestNode(grid *models.Grid) (*models.Node, error) {
+	trees := l.trees
+	if len(trees) == 0 {
+		start, err := grid.GetStart()
+		if err != nil {
+			return nil, err
+		}
+		tree, err := NewShortestPathTree(grid, start)
+		if err != nil {
+			return nil, err
+		}
+		trees = []*ShortestPathTree{tree}
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var farthest *models.Node
+	farthestDistance := 0.0
+	for _, row := range nodes {
+		for _, node := range row {
+			closest := math.Inf(1)
+			for _, tree := range trees {
+				closest = math.Min(closest, tree.Distance(node))
+			}
+			if !math.IsInf(closest, 1) && closest > farthestDistance {
+				farthest, farthestDistance = node, closest
+			}
+		}
+	}
+	return farthest, nil
+}
+
+// Estimate returns the largest lower bound on the distance between node and target over all landmarks
+func (l *Landmarks) Estimate(node, target *models.Node) float64 {
+	estimate := 0.0
+	for _, tree := range l.trees {
+		toNode, toTarget := tree.Distance(node), tree.Distance(target)
+		if math.IsInf(toNode, 1) || math.IsInf(toTarget, 1) {
+			continue
+		}
+		estimate = math.Max(estimate, math.Abs(toTarget-toNode))
+	}
+	return estimate
+}
+
+// GetNodes returns the cells of the selected landmarks
+func (l *Landmarks) GetNodes() []*models.Node {
+	nodes := make([]*models.Node, len(l.trees))
+	for i, tree := range l.trees {
+		nodes[i] = tree.source
+	}
+	return nodes
+}
@@ -0,0 +1,319 @@
+package algorithms
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// TargetPolicy decides where a moving target goes on its turn
+type TargetPolicy interface {
+	// Next returns the cell the target moves to, or the target itself to stay. It never returns the hunter.
+	Next(grid *models.Grid, target, hunter *models.Node) (*models.Node, error)
+}
+
+// ScriptedTarget patrols a fixed route back and forth
+type ScriptedTarget struct {
+	route     []models.Point
+	index     int
+	direction int
+}
+
+// NewScriptedTarget builds the route through stops along shortest paths, the target begins at the first stop
+func NewScriptedTarget(grid *models.Grid, stops []*models.Node) (*ScriptedTarget, error) {
+	if len(stops) == 0 {
+		return nil, errors.New("route needs at least one stop")
+	}
+	route := []models.Point{{Dx: stops[0].X, Dy: stops[0].Y}}
+	for i := 1; i < len(stops); i++ {
+		tree, err := NewShortestPathTree(grid, stops[i-1])
+		if err != nil {
+			return nil, err
+		}
+		cells, err := tree.PathTo(stops[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, cell := range cells[1:] {
+			route = append(route, models.Point{Dx: cell.X, Dy: cell.Y})
+		}
+	}
+	return &ScriptedTarget{route: route, direction: 1}, nil
+}
+
+func (s *ScriptedTarget) Next(grid *models.Grid, target, hunter *models.Node) (*models.Node, error) {
+	if len(s.route) < 2 {
+		return target, nil
+	}
+	next := s.index + s.direction
+	if next < 0 || next >= len(s.route) {
+		s.direction = -s.direction
+		next = s.index + s.direction
+	}
+	node, err := grid.GetNode(s.route[next].Dx, s.route[next].Dy)
+	if err != nil {
+		return nil, err
+	}
+	// The target waits for the hunter to move out of its way
+	if node == hunter {
+		return target, nil
+	}
+	s.index = next
+	return node, nil
+}
+
+// RandomWalkTarget moves to a random open neighbour every turn
+type RandomWalkTarget struct {
+	rng *rand.Rand
+}
+
+func NewRandomWalkTarget(seed int64) *RandomWalkTarget {
+	return &RandomWalkTarget{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (r *RandomWalkTarget) Next(grid *models.Grid, target, hunter *models.Node) (*models.Node, error) {
+	neighbors, err := grid.GetNeighbors(target)
+	if err != nil {
+		return nil, err
+	}
+	var open []*models.Node
+	for _, neighbor := range neighbors {
+		if !neighbor.IsWall && neighbor != hunter {
+			open = append(open, neighbor)
+		}
+	}
+	if len(open) == 0 {
+		return target, nil
+	}
+	return open[r.rng.Intn(len(open))], nil
+}
+
+// EvasiveTarget greedily moves to the neighbour farthest from the hunter by true distance
+type EvasiveTarget struct{}
+
+func (EvasiveTarget) Next(grid *models.Grid, target, hunter *models.Node) (*models.Node, error) {
+	tree, err := NewShortestPathTree(grid, hunter)
+	if err != nil {
+		return nil, err
+	}
+	neighbors, err := grid.GetNeighbors(target)
+	if err != nil {
+		return nil, err
+	}
+	best, bestDistance := target, tree.Distance(target)
+	for _, neighbor := range neighbors {
+		if neighbor.IsWall || neighbor == hunter {
+			continue
+		}
+		distance := tree.Distance(neighbor)
+		// Equally far cells are broken by the straight line, running along a corridor instead of into a corner
+		if distance > bestDistance || distance == bestDistance && heuristic(neighbor, hunter) > heuristic(best, hunter) {
+			best, bestDistance = neighbor, distance
+		}
+	}
+	return best, nil
+}
+
+// Pursuit chases a moving target with Moving Target Adaptive A*. After every search the heuristic of the
+// expanded cells is raised to their true distance to the target, and when the target moves the learned
+// values are lowered by the heuristic of its new cell, which keeps them consistent. Later searches reuse
+// what was learned and expand fewer cells than searching from scratch. The plan is only recomputed when
+// the target leaves it.
+type Pursuit struct {
+	grid     *models.Grid
+	policy   TargetPolicy
+	interval int // The target moves every interval ticks, a slower target can always be caught
+	hunter   *models.Node
+	target   *models.Node
+	learned  map[*models.Node]float64
+	path     []*models.Node // Planned cells from the hunter to the target, both inclusive
+	tick     int
+	caught   bool
+}
+
+// PursuitSnapshot is the state of the chase after a tick
+type PursuitSnapshot struct {
+	Tick     int
+	Nodes    [][]*models.Node // The hunter is the start, the target is the end, cells searched this tick are visited
+	Path     []models.Node    // Cells the hunter plans to take to the target
+	Expanded int
+	Caught   bool
+}
+
+// NewPursuit starts a chase on a copy of grid, the hunter begins at the start and the target at the primary end
+func NewPursuit(grid *models.Grid, policy TargetPolicy, interval int) (*Pursuit, error) {
+	if grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	if policy == nil {
+		return nil, errors.New("policy is nil")
+	}
+	if interval < 1 {
+		return nil, errors.New("target interval must be at least 1")
+	}
+	copied, err := grid.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+	hunter, err := copied.GetStart()
+	if err != nil {
+		return nil, err
+	}
+	target, err := copied.GetEnd()
+	if err != nil {
+		return nil, err
+	}
+	return &Pursuit{
+		grid:     copied,
+		policy:   policy,
+		interval: interval,
+		hunter:   hunter,
+		target:   target,
+		learned:  make(map[*models.Node]float64),
+	}, nil
+}
+
+// Tick lets the target take its turn, replans if needed and moves the hunter one cell
+func (p *Pursuit) Tick() (*PursuitSnapshot, error) {
+	if p.caught {
+		return nil, errors.New("target is caught")
+	}
+	p.tick++
+	if p.tick%p.interval == 0 {
+		next, err := p.policy.Next(p.grid, p.target, p.hunter)
+		if err != nil {
+			return nil, err
+		}
+		p.moveTarget(next)
+	}
+
+	var expanded map[*models.Node]bool
+	if !p.followsPlan() {
+		var err error
+		if expanded, err = p.search(); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.path) > 1 {
+		p.path = p.path[1:]
+		p.hunter = p.path[0]
+	}
+	p.caught = p.hunter == p.target
+	return p.snapshot(expanded)
+}
+
+// IsCaught reports whether the hunter has reached the target
+func (p *Pursuit) IsCaught() bool {
+	return p.caught
+}
+
+func (p *Pursuit) moveTarget(next *models.Node) {
+	if next == p.target {
+		return
+	}
+	correction := p.estimate(next)
+	p.target = next
+	for node, h := range p.learned {
+		if h -= correction; h > 0 {
+			p.learned[node] = h
+		} else {
+			delete(p.learned, node)
+		}
+	}
+}
+
+// followsPlan trims the plan when the target stepped onto it and reports whether it still ends at the target
+func (p *Pursuit) followsPlan() bool {
+	for i, node := range p.path {
+		if node == p.target {
+			p.path = p.path[:i+1]
+			return true
+		}
+	}
+	return false
+}
+
+// estimate is the learned distance to the target, never below the straight line
+func (p *Pursuit) estimate(node *models.Node) float64 {
+	return math.Max(heuristic(node, p.target), p.learned[node])
+}
+
+// search runs A* from the hunter to the target and learns the distances of the expanded cells
+func (p *Pursuit) search() (map[*models.Node]bool, error) {
+	openSet := &datastructures.PriorityQueue[*models.Node]{}
+	openSet.Init()
+	gScore := map[*models.Node]float64{p.hunter: 0}
+	closedSet := make(map[*models.Node]bool)
+	parents := make(map[*models.Node]*models.Node)
+	openSet.Insert(datastructures.NewItem(p.hunter, p.estimate(p.hunter)))
+
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		if current == p.target {
+			for node := range closedSet {
+				p.learned[node] = gScore[current] - gScore[node]
+			}
+			p.path = tracePath(parents, p.hunter, p.target)
+			return closedSet, nil
+		}
+		closedSet[current] = true
+
+		neighbors, err := p.grid.GetNeighbors(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, neighbor := range neighbors {
+			if closedSet[neighbor] || neighbor.IsWall {
+				continue
+			}
+			tentativeGScore := gScore[current] + distBetween(current, neighbor)
+			if g, exists := gScore[neighbor]; exists && tentativeGScore >= g {
+				continue
+			}
+			gScore[neighbor] = tentativeGScore
+			parents[neighbor] = current
+			fScore := tentativeGScore + p.estimate(neighbor)
+			if !openSet.Contains(neighbor) {
+				openSet.Insert(datastructures.NewItem(neighbor, fScore))
+			} else {
+				openSet.Update(neighbor, fScore)
+			}
+		}
+	}
+	return nil, errors.New("path to target not found")
+}
+
+func (p *Pursuit) snapshot(expanded map[*models.Node]bool) (*PursuitSnapshot, error) {
+	copied, err := p.grid.DeepCopy()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := copied.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range nodes {
+		for x, node := range row {
+			node.IsStart = x == p.hunter.X && y == p.hunter.Y
+			node.IsEnd = x == p.target.X && y == p.target.Y
+			node.IsWaypoint = false
+			node.Visited = false
+		}
+	}
+	for node := range expanded {
+		nodes[node.Y][node.X].Visited = true
+	}
+	path := make([]models.Node, len(p.path))
+	for i, node := range p.path {
+		path[i] = *node
+	}
+	return &PursuitSnapshot{
+		Tick:     p.tick,
+		Nodes:    nodes,
+		Path:     path,
+		Expanded: len(expanded),
+		Caught:   p.caught,
+	}, nil
+}
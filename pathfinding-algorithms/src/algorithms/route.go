@@ -0,0 +1,117 @@
+package algorithms
+
+import (
+	"errors"
+	"math"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// searchLegs runs search on every leg between consecutive stops of the grid and concatenates the routes. Each
+// leg targets the next waypoint, the last one targets every end and finishes at whichever is reached first.
+// Visited flags are only reset before the first leg, so the grid keeps the exploration of every leg. Snapshots
+// still queued, such as maze construction frames from Record, are dropped so the replay only shows this search.
+func searchLegs(grid *models.Grid, snapshots *datastructures.Queue[models.Snapshot], search func(leg int, from *models.Node, targets []*models.Node) ([]*models.Node, error)) ([]models.Node, error) {
+	snapshots.Clear()
+	stops, err := grid.GetStops()
+	if err != nil {
+		return nil, err
+	}
+	ends, err := grid.GetEnds()
+	if err != nil {
+		return nil, err
+	}
+	if err := grid.ClearVisited(); err != nil {
+		return nil, err
+	}
+	var route []models.Node
+	for leg := range stops {
+		targets := ends
+		if leg < len(stops)-1 {
+			targets = stops[leg+1 : leg+2]
+		}
+		cells, err := search(leg, stops[leg], targets)
+		if err != nil {
+			return nil, err
+		}
+		// Every leg starts where the previous one ended
+		if leg > 0 {
+			cells = cells[1:]
+		}
+		for _, cell := range cells {
+			route = append(route, *cell)
+		}
+	}
+	return route, nil
+}
+
+// takeSnapshot copies the nodes of the grid, tagged with the leg being searched
+func takeSnapshot(grid *models.Grid, leg int) (models.Snapshot, error) {
+	snapshot, err := grid.DeepCopy()
+	if err != nil {
+		return models.Snapshot{}, err
+	}
+	nodes, err := snapshot.GetNodes()
+	if err != nil {
+		return models.Snapshot{}, err
+	}
+	return models.Snapshot{Leg: leg, Nodes: nodes}, nil
+}
+
+// recordSnapshot queues a snapshot of the grid as it is, for changes made outside the search such as maze
+// generation. These are replayed before the algorithm is solved, until a search drops them.
+func recordSnapshot(grid *models.Grid, snapshots *datastructures.Queue[models.Snapshot]) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	snapshot, err := takeSnapshot(grid, 0)
+	if err != nil {
+		return err
+	}
+	snapshots.Enqueue(snapshot)
+	return nil
+}
+
+// nextSnapshot dequeues the next snapshot to replay, nil once all of them were shown
+func nextSnapshot(snapshots *datastructures.Queue[models.Snapshot]) *models.Snapshot {
+	snapshot, ok := snapshots.Dequeue()
+	if !ok {
+		return nil
+	}
+	return &snapshot
+}
+
+// isStop reports whether the node is the start, end or a waypoint, which are never marked as visited
+func isStop(node *models.Node) bool {
+	return node.IsStart || node.IsEnd || node.IsWaypoint
+}
+
+func isTarget(targets []*models.Node, node *models.Node) bool {
+	for _, target := range targets {
+		if target == node {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestHeuristic is the straight-line distance to the closest target
+func nearestHeuristic(node *models.Node, targets []*models.Node) float64 {
+	estimate := math.Inf(1)
+	for _, target := range targets {
+		estimate = math.Min(estimate, heuristic(node, target))
+	}
+	return estimate
+}
+
+// newOpenSet returns an empty open set of the selected kind, both selections were checked when they were made.
+// Tie breaking only applies to the binary heap, the other open sets order equal priorities themselves.
+func newOpenSet(id datastructures.OpenSetID, tieBreak datastructures.TieBreak, seed int64) datastructures.OpenSet[*models.Node] {
+	if openSet, err := datastructures.NewOpenSet[*models.Node](id); err == nil && id != datastructures.BinaryHeap {
+		return openSet
+	}
+	queue := &datastructures.PriorityQueue[*models.Node]{}
+	_ = queue.SetTieBreak(tieBreak, seed)
+	queue.Init()
+	return queue
+}
@@ -0,0 +1,113 @@
+package algorithms
+
+import (
+	"errors"
+	"math"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// ShortestPathTree holds the distance from the closest source to every cell of the grid, along with the
+// predecessor of each cell on its shortest path
+type ShortestPathTree struct {
+	source    *models.Node // The first source
+	distances [][]float64
+	parents   [][]*models.Node
+}
+
+// NewShortestPathTree runs Dijkstra from source, and any further sources at once, over the whole grid
+func NewShortestPathTree(grid *models.Grid, source *models.Node, sources ...*models.Node) (*ShortestPathTree, error) {
+	return newShortestPathTree(grid, distBetween, source, sources...)
+}
+
+// NewUnweightedShortestPathTree is NewShortestPathTree counting every move as one step, ignoring weights and
+// diagonals. This is the distance in timesteps the multi-agent planners search over.
+func NewUnweightedShortestPathTree(grid *models.Grid, source *models.Node, sources ...*models.Node) (*ShortestPathTree, error) {
+	return newShortestPathTree(grid, unitStep, source, sources...)
+}
+
+func unitStep(_, _ *models.Node) float64 {
+	return 1
+}
+
+// newShortestPathTree builds the tree with step as the cost of moving between two neighbours
+func newShortestPathTree(grid *models.Grid, step func(from, to *models.Node) float64, source *models.Node, sources ...*models.Node) (*ShortestPathTree, error) {
+	if grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	sources = append([]*models.Node{source}, sources...)
+	for _, source := range sources {
+		if source == nil {
+			return nil, errors.New("source is nil")
+		}
+	}
+	tree := &ShortestPathTree{
+		source:    source,
+		distances: make([][]float64, grid.GetHeight()),
+		parents:   make([][]*models.Node, grid.GetHeight()),
+	}
+	for y := range tree.distances {
+		tree.distances[y] = make([]float64, grid.GetWidth())
+		tree.parents[y] = make([]*models.Node, grid.GetWidth())
+		for x := range tree.distances[y] {
+			tree.distances[y][x] = math.Inf(1)
+		}
+	}
+
+	// Cells are queued once and lowered in place with Update, the queue is indexed so this stays O(log n)
+	// even though the tree spans the whole grid
+	closed := make([][]bool, grid.GetHeight())
+	for y := range closed {
+		closed[y] = make([]bool, grid.GetWidth())
+	}
+	openSet := &datastructures.PriorityQueue[*models.Node]{}
+	openSet.Init()
+	for _, source := range sources {
+		tree.distances[source.Y][source.X] = 0
+		openSet.Insert(datastructures.NewItem(source, 0))
+	}
+
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		closed[current.Y][current.X] = true
+
+		neighbors, err := grid.GetNeighbors(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, neighbor := range neighbors {
+			if neighbor.IsWall || closed[neighbor.Y][neighbor.X] {
+				continue
+			}
+			tentativeDistance := tree.distances[current.Y][current.X] + step(current, neighbor)
+			if tentativeDistance < tree.distances[neighbor.Y][neighbor.X] {
+				tree.distances[neighbor.Y][neighbor.X] = tentativeDistance
+				tree.parents[neighbor.Y][neighbor.X] = current
+				if !openSet.Contains(neighbor) {
+					openSet.Insert(datastructures.NewItem(neighbor, tentativeDistance))
+				} else {
+					openSet.Update(neighbor, tentativeDistance)
+				}
+			}
+		}
+	}
+	return tree, nil
+}
+
+// Distance returns the length of the shortest path from the closest source to node, +Inf when unreachable
+func (s *ShortestPathTree) Distance(node *models.Node) float64 {
+	return s.distances[node.Y][node.X]
+}
+
+// PathTo returns the cells on the shortest path from the closest source to node, both inclusive
+func (s *ShortestPathTree) PathTo(node *models.Node) ([]*models.Node, error) {
+	if math.IsInf(s.Distance(node), 1) {
+		return nil, errors.New("node is unreachable")
+	}
+	// Only sources are reachable without a parent
+	path := []*models.Node{node}
+	for current := s.parents[node.Y][node.X]; current != nil; current = s.parents[current.Y][current.X] {
+		path = append(path, current)
+	}
+	return reversed(path), nil
+}
@@ -0,0 +1,185 @@
+package algorithms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"pathfinding-algorithms/models"
+)
+
+// Goal sets up to this size are ordered exactly, the dynamic program grows with 2^n * n^2
+const maxExactTourGoals = 12
+
+// Tour is an order for visiting a set of goals, starting at the start node
+type Tour struct {
+	Order []int // Indices into the goals in visiting order
+	Cost  float64
+	Path  []models.Node
+}
+
+// SolveTour finds a short order to visit every goal from the start node. Pairwise distances come from a
+// Dijkstra search rooted at every stop, small sets are then ordered exactly with the Held-Karp dynamic
+// program and larger ones with nearest neighbour improved by 2-opt.
+func SolveTour(grid *models.Grid, goals []*models.Node) (*Tour, error) {
+	if grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	if len(goals) == 0 {
+		return nil, errors.New("no goals to visit")
+	}
+	start, err := grid.GetStart()
+	if err != nil {
+		return nil, err
+	}
+
+	// Stop 0 is the start, stop i + 1 is goal i. The Dijkstra algorithm stops at the first target it reaches and
+	// snapshots the grid at every expansion, so it would take a search per pair of stops. A shortest path tree
+	// runs the same search over the whole grid once per stop, without touching the grid.
+	stops := append([]*models.Node{start}, goals...)
+	trees := make([]*ShortestPathTree, len(stops))
+	for i, stop := range stops {
+		if trees[i], err = NewShortestPathTree(grid, stop); err != nil {
+			return nil, err
+		}
+	}
+	distances := make([][]float64, len(stops))
+	for i := range stops {
+		distances[i] = make([]float64, len(stops))
+		for j, stop := range stops {
+			distances[i][j] = trees[i].Distance(stop)
+		}
+	}
+	for i, goal := range goals {
+		if math.IsInf(distances[0][i+1], 1) {
+			return nil, fmt.Errorf("goal (%d,%d) is unreachable", goal.X, goal.Y)
+		}
+	}
+
+	var order []int
+	if len(goals) <= maxExactTourGoals {
+		order = exactTourOrder(distances)
+	} else {
+		order = twoOpt(distances, nearestNeighborOrder(distances))
+	}
+
+	tour := &Tour{}
+	previous := 0
+	path := []models.Node{*start}
+	for _, stop := range order {
+		cells, err := trees[previous].PathTo(stops[stop])
+		if err != nil {
+			return nil, err
+		}
+		for _, cell := range cells[1:] {
+			path = append(path, *cell)
+		}
+		tour.Order = append(tour.Order, stop-1)
+		tour.Cost += distances[previous][stop]
+		previous = stop
+	}
+	tour.Path = path
+	return tour, nil
+}
+
+// exactTourOrder solves the open tour from stop 0 with the Held-Karp dynamic program
+func exactTourOrder(distances [][]float64) []int {
+	n := len(distances) - 1
+	full := 1<<n - 1
+	// cost[mask][j] is the cheapest way to visit the goals in mask, finishing at goal j
+	cost := make([][]float64, full+1)
+	parent := make([][]int, full+1)
+	for mask := range cost {
+		cost[mask] = make([]float64, n)
+		parent[mask] = make([]int, n)
+		for j := range cost[mask] {
+			cost[mask][j] = math.Inf(1)
+		}
+	}
+	for j := 0; j < n; j++ {
+		cost[1<<j][j] = distances[0][j+1]
+		parent[1<<j][j] = -1
+	}
+	for mask := 1; mask <= full; mask++ {
+		for j := 0; j < n; j++ {
+			if mask&(1<<j) == 0 || math.IsInf(cost[mask][j], 1) {
+				continue
+			}
+			for k := 0; k < n; k++ {
+				if mask&(1<<k) != 0 {
+					continue
+				}
+				next := mask | 1<<k
+				if candidate := cost[mask][j] + distances[j+1][k+1]; candidate < cost[next][k] {
+					cost[next][k] = candidate
+					parent[next][k] = j
+				}
+			}
+		}
+	}
+
+	last := 0
+	for j := 1; j < n; j++ {
+		if cost[full][j] < cost[full][last] {
+			last = j
+		}
+	}
+	order := make([]int, n)
+	for mask, j, i := full, last, n-1; j >= 0; i-- {
+		order[i] = j + 1
+		mask, j = mask&^(1<<j), parent[mask][j]
+	}
+	return order
+}
+
+// nearestNeighborOrder always moves on to the closest unvisited goal
+func nearestNeighborOrder(distances [][]float64) []int {
+	visited := make([]bool, len(distances))
+	order := make([]int, 0, len(distances)-1)
+	for current := 0; len(order) < len(distances)-1; {
+		next := -1
+		for candidate := 1; candidate < len(distances); candidate++ {
+			if !visited[candidate] && (next < 0 || distances[current][candidate] < distances[current][next]) {
+				next = candidate
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+		current = next
+	}
+	return order
+}
+
+// twoOpt reverses segments of the open tour as long as that shortens it
+func twoOpt(distances [][]float64, order []int) []int {
+	// The tour has no edge after its last goal, so that end of a reversed segment costs nothing
+	distance := func(from, to int) float64 {
+		if from < 0 || to < 0 {
+			return 0
+		}
+		return distances[from][to]
+	}
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(order)-1; i++ {
+			previous := 0
+			if i > 0 {
+				previous = order[i-1]
+			}
+			for k := i + 1; k < len(order); k++ {
+				next := -1
+				if k < len(order)-1 {
+					next = order[k+1]
+				}
+				delta := distance(previous, order[k]) + distance(order[i], next) -
+					distance(previous, order[i]) - distance(order[k], next)
+				if delta < -1e-9 {
+					for a, b := i, k; a < b; a, b = a+1, b-1 {
+						order[a], order[b] = order[b], order[a]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+	return order
+}
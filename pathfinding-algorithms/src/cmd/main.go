@@ -4,13 +4,20 @@ package main
 
 import (
 	"fmt"
+	"math"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/mazes"
 	"pathfinding-algorithms/models"
 	"pathfinding-algorithms/pathfinder"
+	"strings"
 	"syscall/js"
 )
 
 var pf *pathfinder.Pathfinder
 var snapshotPointer *[]uint8
+var snapshotLeg = -1
+var pursuitSnapshot *algorithms.PursuitSnapshot
 
 func main() {
 	c := make(chan struct{}, 0)
@@ -68,6 +75,89 @@ func getEnd() uint64 {
 	return convertNodeLocationToUint64(node.X, node.Y)
 }
 
+//export setWeight
+func setWeight(x, y, weight int) bool {
+	if err := pf.SetWeight(x, y, weight); err != nil {
+		log(fmt.Sprintf("Error setting weight (%v,%v) to %v: %v", x, y, weight, err))
+		return false
+	}
+	return true
+}
+
+// getWeights returns the weight of every node row by row, capped at 255
+//
+//export getWeights
+func getWeights() *[]uint8 {
+	nodes, err := pf.GetNodes()
+	if err != nil {
+		log(fmt.Sprintf("Error getting weights: %v", err))
+		return nil
+	}
+	out := make([]uint8, 0, len(nodes)*len(nodes[0]))
+	for _, row := range nodes {
+		for _, node := range row {
+			out = append(out, uint8(min(max(node.Weight, 1), math.MaxUint8)))
+		}
+	}
+	return &out
+}
+
+//export addEnd
+func addEnd(x, y int) bool {
+	if err := pf.AddEnd(x, y); err != nil {
+		log(fmt.Sprintf("Error adding end (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export removeEnd
+func removeEnd(x, y int) bool {
+	if err := pf.RemoveEnd(x, y); err != nil {
+		log(fmt.Sprintf("Error removing end (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export getNumEnds
+func getNumEnds() int {
+	ends, err := pf.GetEnds()
+	if err != nil {
+		log(fmt.Sprintf("Error getting ends: %v", err))
+		return -1
+	}
+	return len(ends)
+}
+
+// getEnds encodes the end nodes as (x, y) pairs, the primary end first
+//
+//export getEnds
+func getEnds() *[]uint32 {
+	ends, err := pf.GetEnds()
+	if err != nil {
+		log(fmt.Sprintf("Error getting ends: %v", err))
+		return nil
+	}
+	out := make([]uint32, 0, len(ends)*2)
+	for _, node := range ends {
+		out = append(out, uint32(node.X), uint32(node.Y))
+	}
+	return &out
+}
+
+// getReachedEnd returns the end the last path finished at
+//
+//export getReachedEnd
+func getReachedEnd() uint64 {
+	node, err := pf.GetReachedEnd()
+	if err != nil {
+		log(fmt.Sprintf("Error getting reached end: %v", err))
+		return 0
+	}
+	return convertNodeLocationToUint64(node.X, node.Y)
+}
+
 //export setWall
 func setWall(x, y int, isWall bool) bool {
 	err := pf.SetWall(x, y, isWall)
@@ -136,6 +226,12 @@ func convertNodeToUint(node models.Node) uint8 {
 	if node.IsEnd {
 		boolPack |= 1 << 3 // Use bit 3 for IsEnd
 	}
+	if node.IsWaypoint {
+		boolPack |= 1 << 5 // Use bit 5 for IsWaypoint, bit 4 marks the path in the UI
+	}
+	if node.Weight > 1 {
+		boolPack |= 1 << 6 // Use bit 6 for weighted nodes, getWeights has the weights
+	}
 
 	return boolPack
 }
@@ -164,6 +260,8 @@ func getNumNodes() int {
 	return len(grid) * len(grid[0])
 }
 
+// getNumPathNodes returns the number of cells on the route, each is encoded by getPath
+//
 //export getNumPathNodes
 func getNumPathNodes() int {
 	path, err := pf.GetPath()
@@ -190,14 +288,23 @@ func getSnapshot() *[]uint8 {
 	if err != nil || snapshot == nil {
 		return nil
 	}
-	if snapshotPointer == nil || len(*snapshotPointer) != len(snapshot)*len(snapshot[0]) {
-		ptr := make([]uint8, len(snapshot)*len(snapshot[0]))
+	nodes := snapshot.Nodes
+	if snapshotPointer == nil || len(*snapshotPointer) != len(nodes)*len(nodes[0]) {
+		ptr := make([]uint8, len(nodes)*len(nodes[0]))
 		snapshotPointer = &ptr
 	}
-	encodeGrid(snapshot, *snapshotPointer)
+	snapshotLeg = snapshot.Leg
+	encodeGrid(nodes, *snapshotPointer)
 	return snapshotPointer
 }
 
+// getSnapshotLeg returns the route leg of the snapshot last returned by getSnapshot
+//
+//export getSnapshotLeg
+func getSnapshotLeg() int {
+	return snapshotLeg
+}
+
 //export getPath
 func getPath() *[]uint32 {
 	path, err := pf.GetPath()
@@ -205,36 +312,559 @@ func getPath() *[]uint32 {
 		log(fmt.Sprintf("Error getting path: %v", err))
 		return nil
 	}
-	out := make([]uint32, len(path)*4)
+	out := make([]uint32, len(path)*2)
 	encodePath(path, out)
 	return &out
 }
 
-func encodePath(path map[models.Node]models.Node, out []uint32) {
-	i := 0
-	for prevNode, curNode := range path {
-		// Example encoding: node.X, node.Y -> nextNode.X, nextNode.Y
-		// Adjust based on your actual node structure and encoding needs
-		if i < len(path) {
-			index := i * 4
-			out[index] = uint32(prevNode.X)
-			out[index+1] = uint32(prevNode.Y)
-			out[index+2] = uint32(curNode.X)
-			out[index+3] = uint32(curNode.Y)
-			i++
-		}
+// encodePath writes the cells of the route in order as (x, y) pairs. Routes through waypoints can pass a cell
+// more than once, so the UI draws them in order rather than linking each cell to the one before it.
+func encodePath(path []models.Node, out []uint32) {
+	for i, node := range path {
+		out[i*2] = uint32(node.X)
+		out[i*2+1] = uint32(node.Y)
+	}
+}
+
+//export addWaypoint
+func addWaypoint(x, y int) bool {
+	if err := pf.AddWaypoint(x, y); err != nil {
+		log(fmt.Sprintf("Error adding waypoint (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export removeWaypoint
+func removeWaypoint(x, y int) bool {
+	if err := pf.RemoveWaypoint(x, y); err != nil {
+		log(fmt.Sprintf("Error removing waypoint (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export getNumWaypoints
+func getNumWaypoints() int {
+	waypoints, err := pf.GetWaypoints()
+	if err != nil {
+		log(fmt.Sprintf("Error getting waypoints: %v", err))
+		return -1
+	}
+	return len(waypoints)
+}
+
+// getWaypoints encodes the waypoints in visiting order as (x, y) pairs
+//
+//export getWaypoints
+func getWaypoints() *[]uint32 {
+	waypoints, err := pf.GetWaypoints()
+	if err != nil {
+		log(fmt.Sprintf("Error getting waypoints: %v", err))
+		return nil
+	}
+	out := make([]uint32, 0, len(waypoints)*2)
+	for _, node := range waypoints {
+		out = append(out, uint32(node.X), uint32(node.Y))
+	}
+	return &out
+}
+
+//export addTourGoal
+func addTourGoal(x, y int) bool {
+	if err := pf.AddTourGoal(x, y); err != nil {
+		log(fmt.Sprintf("Error adding tour goal (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export removeTourGoal
+func removeTourGoal(x, y int) bool {
+	if err := pf.RemoveTourGoal(x, y); err != nil {
+		log(fmt.Sprintf("Error removing tour goal (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export clearTourGoals
+func clearTourGoals() bool {
+	pf.ClearTourGoals()
+	return true
+}
+
+//export solveTour
+func solveTour() bool {
+	if _, err := pf.SolveTour(); err != nil {
+		log(fmt.Sprintf("Error solving tour: %v", err))
+		return false
+	}
+	return true
+}
+
+//export getTourCost
+func getTourCost() float64 {
+	tour, err := pf.GetTour()
+	if err != nil {
+		log(fmt.Sprintf("Error getting tour: %v", err))
+		return -1
+	}
+	return tour.Cost
+}
+
+//export getNumTourGoals
+func getNumTourGoals() int {
+	tour, err := pf.GetTour()
+	if err != nil {
+		log(fmt.Sprintf("Error getting tour: %v", err))
+		return -1
+	}
+	return len(tour.Order)
+}
+
+// getTourOrder returns the indices of the goals, in the order they were added, in visiting order
+//
+//export getTourOrder
+func getTourOrder() *[]uint32 {
+	tour, err := pf.GetTour()
+	if err != nil {
+		log(fmt.Sprintf("Error getting tour: %v", err))
+		return nil
 	}
+	out := make([]uint32, len(tour.Order))
+	for i, goal := range tour.Order {
+		out[i] = uint32(goal)
+	}
+	return &out
 }
 
+//export getNumTourPathNodes
+func getNumTourPathNodes() int {
+	tour, err := pf.GetTour()
+	if err != nil {
+		log(fmt.Sprintf("Error getting tour: %v", err))
+		return -1
+	}
+	return len(tour.Path)
+}
+
+// getTourPath encodes the full cell path of the tour the same way as getPath
+//
+//export getTourPath
+func getTourPath() *[]uint32 {
+	tour, err := pf.GetTour()
+	if err != nil {
+		log(fmt.Sprintf("Error getting tour: %v", err))
+		return nil
+	}
+	out := make([]uint32, len(tour.Path)*2)
+	encodePath(tour.Path, out)
+	return &out
+}
+
+// setTieBreak selects how an algorithm orders open nodes with equal priority: 0 any, 1 larger g, 2 smaller h,
+// 3 first in, 4 last in, 5 random from the seed. Find the path again to see the difference.
+//
+//export setTieBreak
+func setTieBreak(algorithm int, tieBreak int, seed int64) bool {
+	if err := pf.SetTieBreak(pathfinder.Algorithm(algorithm), datastructures.TieBreak(tieBreak), seed); err != nil {
+		log(fmt.Sprintf("Error setting tie break: %v", err))
+		return false
+	}
+	return true
+}
+
+// setOpenSet selects where an algorithm keeps its open nodes: 0 binary heap, 1 bucket queue, 2 radix heap
+//
+//export setOpenSet
+func setOpenSet(algorithm int, openSet int) bool {
+	if err := pf.SetOpenSet(pathfinder.Algorithm(algorithm), datastructures.OpenSetID(openSet)); err != nil {
+		log(fmt.Sprintf("Error setting open set: %v", err))
+		return false
+	}
+	return true
+}
+
+// generateMaze builds a maze with the given generator, braid is the fraction of dead ends turned into loops.
+// A seed of 0 picks a new one. Returns the seed used, or 0 on failure.
+//
 //export generateMaze
-func generateMaze() bool {
-	if err := pf.GenerateMaze(); err != nil {
+func generateMaze(generatorID int, seed int64, braid float64) int64 {
+	used, err := pf.GenerateMaze(mazes.GeneratorID(generatorID), seed, braid)
+	if err != nil {
 		log(fmt.Sprintf("Error generating Maze: %v", err))
+		return 0
+	}
+	return used
+}
+
+// generateTerrain fills the grid with weighted noise terrain, getWeights returns the costs. Seeds work as in
+// generateMaze.
+//
+//export generateTerrain
+func generateTerrain(seed int64, scale float64, octaves int) int64 {
+	used, err := pf.GenerateTerrain(seed, scale, octaves)
+	if err != nil {
+		log(fmt.Sprintf("Error generating terrain: %v", err))
+		return 0
+	}
+	return used
+}
+
+// analyzeMaze returns the metrics of the current maze as 12 float64s: cells, dead ends, solution length,
+// decision points, river factor, percentage off the solution, difficulty, then the number of nodes with 0 to 4 open
+// neighbours
+//
+//export analyzeMaze
+func analyzeMaze() *[]float64 {
+	metrics, err := pf.AnalyzeMaze()
+	if err != nil {
+		log(fmt.Sprintf("Error analyzing maze: %v", err))
+		return nil
+	}
+	out := []float64{
+		float64(metrics.Cells),
+		float64(metrics.DeadEnds),
+		float64(metrics.SolutionLength),
+		float64(metrics.DecisionPoints),
+		metrics.RiverFactor,
+		metrics.OffSolution,
+		metrics.Difficulty,
+	}
+	for _, count := range metrics.Branching {
+		out = append(out, float64(count))
+	}
+	return &out
+}
+
+// getMazeGenerators lists the available generators as UTF-8 text, one "id<TAB>name" line per generator
+//
+//export getMazeGenerators
+func getMazeGenerators() *[]uint8 {
+	out := []uint8(encodeMazeGenerators())
+	return &out
+}
+
+//export getMazeGeneratorsLength
+func getMazeGeneratorsLength() int {
+	return len(encodeMazeGenerators())
+}
+
+func encodeMazeGenerators() string {
+	var text strings.Builder
+	for _, generator := range pf.GetMazeGenerators() {
+		fmt.Fprintf(&text, "%d\t%s\n", generator.ID, generator.Name)
+	}
+	return text.String()
+}
+
+//export getHierarchySize
+func getHierarchySize() int {
+	hierarchy, err := pf.GetHierarchy()
+	if err != nil {
+		log(fmt.Sprintf("Error getting hierarchy: %v", err))
+		return -1
+	}
+	return 2 + len(hierarchy.Clusters)*4 + len(hierarchy.Edges)*5
+}
+
+// getHierarchy encodes the clusters and abstract edges of a hierarchical algorithm as
+// [numClusters, numEdges, (x, y, width, height)..., (x1, y1, x2, y2, inter)...]
+//
+//export getHierarchy
+func getHierarchy() *[]uint32 {
+	hierarchy, err := pf.GetHierarchy()
+	if err != nil {
+		log(fmt.Sprintf("Error getting hierarchy: %v", err))
+		return nil
+	}
+	out := make([]uint32, 0, 2+len(hierarchy.Clusters)*4+len(hierarchy.Edges)*5)
+	out = append(out, uint32(len(hierarchy.Clusters)), uint32(len(hierarchy.Edges)))
+	for _, c := range hierarchy.Clusters {
+		out = append(out, uint32(c.X), uint32(c.Y), uint32(c.Width), uint32(c.Height))
+	}
+	for _, edge := range hierarchy.Edges {
+		var inter uint32 = 0
+		if edge.Inter {
+			inter = 1
+		}
+		out = append(out, uint32(edge.From.X), uint32(edge.From.Y), uint32(edge.To.X), uint32(edge.To.Y), inter)
+	}
+	return &out
+}
+
+//export setLandmarks
+func setLandmarks(count int) bool {
+	if err := pf.SetLandmarks(count); err != nil {
+		log(fmt.Sprintf("Error setting %d landmarks: %v", count, err))
+		return false
+	}
+	return true
+}
+
+//export addLandmark
+func addLandmark(x, y int) bool {
+	if err := pf.AddLandmark(x, y); err != nil {
+		log(fmt.Sprintf("Error adding landmark (%v,%v): %v", x, y, err))
+		return false
+	}
+	return true
+}
+
+//export clearLandmarks
+func clearLandmarks() bool {
+	if err := pf.ClearLandmarks(); err != nil {
+		log(fmt.Sprintf("Error clearing landmarks: %v", err))
+		return false
+	}
+	return true
+}
+
+//export getNumLandmarks
+func getNumLandmarks() int {
+	landmarks, err := pf.GetLandmarks()
+	if err != nil {
+		log(fmt.Sprintf("Error getting landmarks: %v", err))
+		return -1
+	}
+	return len(landmarks)
+}
+
+// getLandmarks encodes the landmark cells as (x, y) pairs
+//
+//export getLandmarks
+func getLandmarks() *[]uint32 {
+	landmarks, err := pf.GetLandmarks()
+	if err != nil {
+		log(fmt.Sprintf("Error getting landmarks: %v", err))
+		return nil
+	}
+	out := make([]uint32, 0, len(landmarks)*2)
+	for _, node := range landmarks {
+		out = append(out, uint32(node.X), uint32(node.Y))
+	}
+	return &out
+}
+
+// getFlowField encodes the flow field as (cost, dx, dy) for every cell in row order, unreachable cells
+// have a cost of -1
+//
+//export getFlowField
+func getFlowField() *[]float32 {
+	field, err := pf.GetFlowField()
+	if err != nil {
+		log(fmt.Sprintf("Error getting flow field: %v", err))
+		return nil
+	}
+	out := make([]float32, 0, len(field.Costs)*len(field.Costs[0])*3)
+	for y, row := range field.Costs {
+		for x, cost := range row {
+			if math.IsInf(cost, 1) {
+				cost = -1
+			}
+			direction := field.Directions[y][x]
+			out = append(out, float32(cost), float32(direction.Dx), float32(direction.Dy))
+		}
+	}
+	return &out
+}
+
+//export addAgent
+func addAgent(startX, startY, goalX, goalY int) int {
+	id, err := pf.AddAgent(startX, startY, goalX, goalY)
+	if err != nil {
+		log(fmt.Sprintf("Error adding agent (%v,%v) -> (%v,%v): %v", startX, startY, goalX, goalY, err))
+		return -1
+	}
+	return id
+}
+
+//export removeAgent
+func removeAgent(id int) bool {
+	if err := pf.RemoveAgent(id); err != nil {
+		log(fmt.Sprintf("Error removing agent %v: %v", id, err))
+		return false
+	}
+	return true
+}
+
+//export clearAgents
+func clearAgents() bool {
+	pf.ClearAgents()
+	return true
+}
+
+//export solveAgents
+func solveAgents(planner pathfinder.Planner) bool {
+	if err := pf.SolveAgents(planner); err != nil {
+		log(fmt.Sprintf("Error solving agents with planner %v: %v", planner, err))
+		return false
+	}
+	return true
+}
+
+//export setAgentWindow
+func setAgentWindow(window int) bool {
+	if err := pf.SetAgentWindow(window); err != nil {
+		log(fmt.Sprintf("Error setting agent window to %v: %v", window, err))
 		return false
 	}
 	return true
 }
 
+//export getNumAgentConflicts
+func getNumAgentConflicts() int {
+	conflicts, err := pf.GetAgentConflicts()
+	if err != nil {
+		log(fmt.Sprintf("Error getting agent conflicts: %v", err))
+		return -1
+	}
+	return len(conflicts)
+}
+
+// getAgentConflicts encodes the collisions of the last solution as (agentA, agentB, x, y, t, edge)
+//
+//export getAgentConflicts
+func getAgentConflicts() *[]uint32 {
+	conflicts, err := pf.GetAgentConflicts()
+	if err != nil {
+		log(fmt.Sprintf("Error getting agent conflicts: %v", err))
+		return nil
+	}
+	out := make([]uint32, 0, len(conflicts)*6)
+	for _, c := range conflicts {
+		out = append(out, uint32(c.AgentA), uint32(c.AgentB), uint32(c.To.X), uint32(c.To.Y), uint32(c.T), uint32(boolToInt32(c.Edge)))
+	}
+	return &out
+}
+
+//export getNumAgentPathSteps
+func getNumAgentPathSteps(id int) int {
+	path, err := pf.GetAgentPath(id)
+	if err != nil {
+		log(fmt.Sprintf("Error getting path of agent %v: %v", id, err))
+		return -1
+	}
+	return len(path)
+}
+
+// getAgentPath encodes the timed path of an agent as (x, y, t) triples
+//
+//export getAgentPath
+func getAgentPath(id int) *[]uint32 {
+	path, err := pf.GetAgentPath(id)
+	if err != nil {
+		log(fmt.Sprintf("Error getting path of agent %v: %v", id, err))
+		return nil
+	}
+	out := make([]uint32, 0, len(path)*3)
+	for _, step := range path {
+		out = append(out, uint32(step.X), uint32(step.Y), uint32(step.T))
+	}
+	return &out
+}
+
+// getAgentSnapshot encodes the next planner step as
+// [node, parent, cost, numConstraints, hasConflict, agentA, agentB, x, y, t, edge, constraints...]
+// where each constraint is (agent, fromX, fromY, toX, toY, t, edge), parent is -1 for the root
+//
+//export getAgentSnapshot
+func getAgentSnapshot() *[]int32 {
+	snapshot, err := pf.GetAgentSnapshot()
+	if err != nil || snapshot == nil {
+		return nil
+	}
+	out := make([]int32, 11, 11+len(snapshot.Constraints)*7)
+	out[0], out[1], out[2], out[3] = int32(snapshot.Node), int32(snapshot.Parent), int32(snapshot.Cost), int32(len(snapshot.Constraints))
+	if conflict := snapshot.Conflict; conflict != nil {
+		out[4], out[5], out[6] = 1, int32(conflict.AgentA), int32(conflict.AgentB)
+		out[7], out[8], out[9], out[10] = int32(conflict.To.X), int32(conflict.To.Y), int32(conflict.T), boolToInt32(conflict.Edge)
+	}
+	for _, c := range snapshot.Constraints {
+		out = append(out, int32(c.Agent), int32(c.From.X), int32(c.From.Y), int32(c.To.X), int32(c.To.Y), int32(c.T), boolToInt32(c.Edge))
+	}
+	return &out
+}
+
+// startPursuit begins a chase with the given target policy, the seed drives the random walk
+//
+//export startPursuit
+func startPursuit(policy int, seed int64) bool {
+	if err := pf.StartPursuit(pathfinder.TargetPolicy(policy), seed); err != nil {
+		log(fmt.Sprintf("Error starting pursuit: %v", err))
+		return false
+	}
+	pursuitSnapshot = nil
+	return true
+}
+
+//export setTargetInterval
+func setTargetInterval(interval int) bool {
+	if err := pf.SetTargetInterval(interval); err != nil {
+		log(fmt.Sprintf("Error setting target interval: %v", err))
+		return false
+	}
+	return true
+}
+
+//export stopPursuit
+func stopPursuit() {
+	pf.StopPursuit()
+	pursuitSnapshot = nil
+}
+
+// tick advances the pursuit and returns the grid encoded like getSnapshot. The tick that catches the target still
+// returns its grid, later ticks return nil without an error.
+//
+//export tick
+func tick() *[]uint8 {
+	if isTargetCaught() {
+		return nil
+	}
+	snapshot, err := pf.Tick()
+	if err != nil {
+		log(fmt.Sprintf("Error advancing pursuit: %v", err))
+		return nil
+	}
+	pursuitSnapshot = snapshot
+	out := make([]uint8, len(snapshot.Nodes)*len(snapshot.Nodes[0]))
+	encodeGrid(snapshot.Nodes, out)
+	return &out
+}
+
+//export isTargetCaught
+func isTargetCaught() bool {
+	return pursuitSnapshot != nil && pursuitSnapshot.Caught
+}
+
+//export getNumPursuitPathNodes
+func getNumPursuitPathNodes() int {
+	if pursuitSnapshot == nil {
+		return 0
+	}
+	return len(pursuitSnapshot.Path)
+}
+
+// getPursuitPath encodes the plan of the hunter after the last tick like getPath
+//
+//export getPursuitPath
+func getPursuitPath() *[]uint32 {
+	if pursuitSnapshot == nil {
+		return nil
+	}
+	out := make([]uint32, len(pursuitSnapshot.Path)*2)
+	encodePath(pursuitSnapshot.Path, out)
+	return &out
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func log(a string) {
 	js.Global().Get("console").Get("log").Invoke(a)
 }
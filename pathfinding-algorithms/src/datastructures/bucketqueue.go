@@ -0,0 +1,79 @@
+package datastructures
+
+// BucketQueue is Dial's bucket queue. Items are kept in one bucket per resolution step of priority and popped by
+// sweeping the buckets upwards, which makes every operation O(1) apart from skipping empty buckets. Priorities
+// popped must not decrease, as in Dijkstra or A* with a consistent heuristic. Items within one resolution step
+// of each other count as equal, the last one in is popped first.
+type BucketQueue[T comparable] struct {
+	resolution float64
+	buckets    [][]*Item[T]
+	current    uint64 // Lowest bucket that may hold items
+	indexed    map[T]*Item[T]
+}
+
+func NewBucketQueue[T comparable](resolution float64) *BucketQueue[T] {
+	return &BucketQueue[T]{resolution: resolution}
+}
+
+// Init initializes or clears the bucket queue
+func (b *BucketQueue[T]) Init() {
+	b.buckets = nil
+	b.current = 0
+	b.indexed = make(map[T]*Item[T])
+}
+
+func (b *BucketQueue[T]) Len() int { return len(b.indexed) }
+
+func (b *BucketQueue[T]) Insert(item *Item[T]) {
+	b.indexed[item.value] = item
+	b.place(item)
+}
+
+func (b *BucketQueue[T]) place(item *Item[T]) {
+	item.key = monotoneKey(item.priority, b.resolution, b.current)
+	for uint64(len(b.buckets)) <= item.key {
+		b.buckets = append(b.buckets, nil)
+	}
+	item.bucket = int(item.key)
+	item.index = len(b.buckets[item.key])
+	b.buckets[item.key] = append(b.buckets[item.key], item)
+}
+
+// PopMin removes and returns an item of the lowest bucket, nil if the queue is empty
+func (b *BucketQueue[T]) PopMin() *Item[T] {
+	if len(b.indexed) == 0 {
+		return nil
+	}
+	for len(b.buckets[b.current]) == 0 {
+		b.current++
+	}
+	bucket := &b.buckets[b.current]
+	item := (*bucket)[len(*bucket)-1]
+	removeFromBucket(bucket, item)
+	delete(b.indexed, item.value)
+	return item
+}
+
+func (b *BucketQueue[T]) Contains(value T) bool {
+	_, ok := b.indexed[value]
+	return ok
+}
+
+// Update moves the item to the bucket of its new priority
+func (b *BucketQueue[T]) Update(value T, newPriority float64) {
+	item, ok := b.indexed[value]
+	if !ok {
+		return
+	}
+	removeFromBucket(&b.buckets[item.bucket], item)
+	item.priority = newPriority
+	b.place(item)
+}
+
+// UpdateScored updates the priority, g and h only matter for tie breaking in a PriorityQueue
+func (b *BucketQueue[T]) UpdateScored(value T, newPriority, g, h float64) {
+	if item, ok := b.indexed[value]; ok {
+		item.g, item.h = g, h
+	}
+	b.Update(value, newPriority)
+}
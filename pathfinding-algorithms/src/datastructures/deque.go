@@ -0,0 +1,150 @@
+package datastructures
+
+// minDequeCapacity is the smallest ring buffer a deque allocates, and below which it never shrinks on its own
+const minDequeCapacity = 8
+
+// Deque is a double ended queue of T. Items are kept in a ring buffer that doubles when full and halves when
+// three quarters empty, so pushing and popping at either end is amortized O(1) and a drained deque does not
+// hold on to the memory of a burst. Popped slots are cleared, popped items are not kept reachable.
+// The zero value is an empty deque.
+type Deque[T any] struct {
+	items    []T
+	head     int // Index of the front item
+	size     int
+	reserved int // Capacity the buffer does not shrink below
+}
+
+// NewDeque returns an empty deque with room for capacity items, see Reserve
+func NewDeque[T any](capacity int) *Deque[T] {
+	d := &Deque[T]{}
+	d.Reserve(capacity)
+	return d
+}
+
+// PushFront adds an item before the front of the deque
+func (d *Deque[T]) PushFront(item T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.items)) % len(d.items)
+	d.items[d.head] = item
+	d.size++
+}
+
+// PushBack adds an item after the back of the deque
+func (d *Deque[T]) PushBack(item T) {
+	d.grow()
+	d.items[(d.head+d.size)%len(d.items)] = item
+	d.size++
+}
+
+// PopFront removes the front item and returns it
+// Returns the zero value and false if the deque is empty
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	item := d.items[d.head]
+	d.items[d.head] = zero // avoid memory leak
+	d.head = (d.head + 1) % len(d.items)
+	d.size--
+	d.shrink()
+	return item, true
+}
+
+// PopBack removes the back item and returns it
+// Returns the zero value and false if the deque is empty
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.size == 0 {
+		return zero, false
+	}
+	i := (d.head + d.size - 1) % len(d.items)
+	item := d.items[i]
+	d.items[i] = zero // avoid memory leak
+	d.size--
+	d.shrink()
+	return item, true
+}
+
+// PeekFront returns the front item without removing it
+func (d *Deque[T]) PeekFront() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items[d.head], true
+}
+
+// PeekBack returns the back item without removing it
+func (d *Deque[T]) PeekBack() (T, bool) {
+	if d.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items[(d.head+d.size-1)%len(d.items)], true
+}
+
+// IsEmpty checks if the deque is empty
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// Size returns the number of items in the deque
+func (d *Deque[T]) Size() int {
+	return d.size
+}
+
+// Cap returns the number of items the deque holds before its buffer has to grow
+func (d *Deque[T]) Cap() int {
+	return len(d.items)
+}
+
+// Reserve makes room for capacity items up front, so filling the deque does not reallocate, and keeps the
+// buffer from shrinking below it. A capacity of 0 removes the reservation.
+func (d *Deque[T]) Reserve(capacity int) {
+	d.reserved = max(capacity, 0)
+	if d.reserved > len(d.items) {
+		d.resize(d.reserved)
+	}
+}
+
+// Clip shrinks the buffer to the items in the deque and removes the reservation, releasing all spare memory
+func (d *Deque[T]) Clip() {
+	d.reserved = 0
+	d.resize(d.size)
+}
+
+// Clear removes every item, keeping only the reserved capacity
+func (d *Deque[T]) Clear() {
+	d.items, d.head, d.size = nil, 0, 0
+	if d.reserved > 0 {
+		d.items = make([]T, d.reserved)
+	}
+}
+
+// grow doubles the ring buffer when it is full
+func (d *Deque[T]) grow() {
+	if d.size < len(d.items) {
+		return
+	}
+	d.resize(max(minDequeCapacity, 2*len(d.items)))
+}
+
+// shrink halves the ring buffer when at most a quarter of it is used, halving at a quarter rather than at half
+// keeps alternating pushes and pops from resizing every time
+func (d *Deque[T]) shrink() {
+	half := len(d.items) / 2
+	if d.size > len(d.items)/4 || half < minDequeCapacity || half < d.reserved {
+		return
+	}
+	d.resize(half)
+}
+
+// resize moves the items to a new buffer of the given capacity, unwrapping them to start at index 0
+func (d *Deque[T]) resize(capacity int) {
+	items := make([]T, capacity)
+	for i := 0; i < d.size; i++ {
+		items[i] = d.items[(d.head+i)%len(d.items)]
+	}
+	d.items, d.head = items, 0
+}
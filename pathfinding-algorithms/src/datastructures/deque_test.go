@@ -0,0 +1,75 @@
+package datastructures_test
+
+import (
+	"math/rand"
+	"pathfinding-algorithms/datastructures"
+	"testing"
+)
+
+func TestDequeMatchesSlice(t *testing.T) {
+	deque := &datastructures.Deque[int]{}
+	var want []int
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		switch rng.Intn(4) {
+		case 0:
+			deque.PushFront(i)
+			want = append([]int{i}, want...)
+		case 1:
+			deque.PushBack(i)
+			want = append(want, i)
+		case 2:
+			got, ok := deque.PopFront()
+			if ok != (len(want) > 0) || ok && got != want[0] {
+				t.Fatalf("PopFront returned %d, %v with %v left", got, ok, want)
+			}
+			if ok {
+				want = want[1:]
+			}
+		case 3:
+			got, ok := deque.PopBack()
+			if ok != (len(want) > 0) || ok && got != want[len(want)-1] {
+				t.Fatalf("PopBack returned %d, %v with %v left", got, ok, want)
+			}
+			if ok {
+				want = want[:len(want)-1]
+			}
+		}
+		if deque.Size() != len(want) {
+			t.Fatalf("Size is %d, expected %d", deque.Size(), len(want))
+		}
+		if front, ok := deque.PeekFront(); ok && front != want[0] {
+			t.Fatalf("PeekFront returned %d, expected %d", front, want[0])
+		}
+		if back, ok := deque.PeekBack(); ok && back != want[len(want)-1] {
+			t.Fatalf("PeekBack returned %d, expected %d", back, want[len(want)-1])
+		}
+	}
+}
+
+func TestStackAndQueueOrder(t *testing.T) {
+	stack := &datastructures.Stack[string]{}
+	queue := &datastructures.Queue[string]{}
+	for _, item := range []string{"a", "b", "c"} {
+		stack.Push(item)
+		queue.Enqueue(item)
+	}
+	var popped, dequeued string
+	for !stack.IsEmpty() {
+		item, _ := stack.Pop()
+		popped += item
+	}
+	for !queue.IsEmpty() {
+		item, _ := queue.Dequeue()
+		dequeued += item
+	}
+	if popped != "cba" || dequeued != "abc" {
+		t.Fatalf("Stack popped %q, queue dequeued %q", popped, dequeued)
+	}
+	if _, ok := stack.Pop(); ok {
+		t.Fatalf("Empty stack popped an item")
+	}
+	if _, ok := queue.Dequeue(); ok {
+		t.Fatalf("Empty queue dequeued an item")
+	}
+}
@@ -0,0 +1,66 @@
+package datastructures
+
+import (
+	"errors"
+	"math"
+)
+
+// OpenSet holds the states a search has reached but not expanded yet, popping the one with the lowest priority
+type OpenSet[T comparable] interface {
+	Init()
+	Len() int
+	Insert(item *Item[T])
+	PopMin() *Item[T]
+	Contains(value T) bool
+	Update(value T, newPriority float64)
+	UpdateScored(value T, newPriority, g, h float64)
+}
+
+type OpenSetID int
+
+const (
+	BinaryHeap OpenSetID = iota // PriorityQueue, exact for any priorities and supports tie breaking
+	Buckets                     // BucketQueue
+	Radix                       // RadixHeap
+)
+
+// CostResolution is the priority step of the bucket queue and radix heap. Orthogonal steps on weighted grids
+// cost whole or half units, priorities between two steps are rounded down.
+const CostResolution = 0.5
+
+// NewOpenSet returns an empty open set of the given kind
+func NewOpenSet[T comparable](id OpenSetID) (OpenSet[T], error) {
+	var openSet OpenSet[T]
+	switch id {
+	case BinaryHeap:
+		openSet = &PriorityQueue[T]{}
+	case Buckets:
+		openSet = NewBucketQueue[T](CostResolution)
+	case Radix:
+		openSet = NewRadixHeap[T](CostResolution)
+	default:
+		return nil, errors.New("open set not found")
+	}
+	openSet.Init()
+	return openSet, nil
+}
+
+// monotoneKey converts a priority to whole resolution steps. The bucket queue and radix heap rely on keys never
+// falling below the last popped key, a slightly inconsistent heuristic is clamped to it instead.
+func monotoneKey(priority, resolution float64, last uint64) uint64 {
+	key := uint64(math.Max(0, priority/resolution+1e-9))
+	if key < last {
+		return last
+	}
+	return key
+}
+
+// removeFromBucket takes the item out of its bucket by moving the last item of the bucket into its place
+func removeFromBucket[T comparable](bucket *[]*Item[T], item *Item[T]) {
+	last := (*bucket)[len(*bucket)-1]
+	(*bucket)[item.index] = last
+	last.index = item.index
+	(*bucket)[len(*bucket)-1] = nil // avoid memory leak
+	*bucket = (*bucket)[:len(*bucket)-1]
+	item.index = -1
+}
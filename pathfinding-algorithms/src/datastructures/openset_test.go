@@ -0,0 +1,89 @@
+package datastructures_test
+
+import (
+	"fmt"
+	"math/rand"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+	"testing"
+)
+
+var openSets = map[string]datastructures.OpenSetID{
+	"BinaryHeap": datastructures.BinaryHeap,
+	"Buckets":    datastructures.Buckets,
+	"Radix":      datastructures.Radix,
+}
+
+func TestOpenSetsPopInPriorityOrder(t *testing.T) {
+	for name, id := range openSets {
+		openSet, err := datastructures.NewOpenSet[*models.Node](id)
+		if err != nil {
+			t.Fatalf("NewOpenSet(%s) failed: %v", name, err)
+		}
+		rng := rand.New(rand.NewSource(1))
+		nodes := make([]*models.Node, 300)
+		for i := range nodes {
+			nodes[i] = &models.Node{X: i}
+			openSet.Insert(datastructures.NewItem(nodes[i], float64(rng.Intn(400))*datastructures.CostResolution))
+		}
+		// Lower some priorities, as a search does when it finds a shorter path
+		priorities := make(map[*models.Node]float64)
+		for i := 0; i < 100; i++ {
+			node := nodes[rng.Intn(len(nodes))]
+			priorities[node] = float64(rng.Intn(50)) * datastructures.CostResolution
+			openSet.Update(node, priorities[node])
+		}
+
+		last, popped := -1.0, 0
+		for openSet.Len() > 0 {
+			item := openSet.PopMin()
+			if item.GetPriority() < last {
+				t.Fatalf("%s: popped priority %v after %v", name, item.GetPriority(), last)
+			}
+			if p, ok := priorities[item.GetValue()]; ok && p != item.GetPriority() {
+				t.Fatalf("%s: node popped with priority %v, updated to %v", name, item.GetPriority(), p)
+			}
+			if openSet.Contains(item.GetValue()) {
+				t.Fatalf("%s: popped node is still contained", name)
+			}
+			last = item.GetPriority()
+			popped++
+		}
+		if popped != len(nodes) {
+			t.Fatalf("%s: popped %d of %d nodes", name, popped, len(nodes))
+		}
+	}
+	if _, err := datastructures.NewOpenSet[*models.Node](datastructures.Radix + 1); err == nil {
+		t.Fatalf("Unknown open set was accepted")
+	}
+}
+
+// BenchmarkOpenSets floods a 500x500 grid with every open set, once with uniform costs and once with random
+// weights from 1 to 9
+func BenchmarkOpenSets(b *testing.B) {
+	const side = 500
+	uniform, err := models.NewGrid(side, side)
+	if err != nil {
+		b.Fatal(err)
+	}
+	weighted, _ := models.NewGrid(side, side)
+	rng := rand.New(rand.NewSource(1))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			weighted.SetWeight(x, y, 1+rng.Intn(9))
+		}
+	}
+	for _, grid := range []struct {
+		name string
+		grid *models.Grid
+	}{{"Uniform", uniform}, {"Weighted", weighted}} {
+		for _, name := range []string{"BinaryHeap", "Buckets", "Radix"} {
+			b.Run(fmt.Sprintf("%s/%s", grid.name, name), func(b *testing.B) {
+				openSet, _ := datastructures.NewOpenSet[*models.Node](openSets[name])
+				for i := 0; i < b.N; i++ {
+					dijkstra(b, grid.grid, openSet)
+				}
+			})
+		}
+	}
+}
@@ -2,84 +2,192 @@ package datastructures
 
 import (
 	"container/heap"
-	"pathfinding-algorithms/models"
+	"errors"
+	"math"
+	"math/rand"
 )
 
-type Item struct {
-	node     *models.Node // The value of the item; arbitrary.
-	priority float64      // The priority of the item in the queue.
-	index    int          // The index of the item in the heap.
+// Item holds a value in a priority queue, T is the search state, a *models.Node for the grid searches
+type Item[T comparable] struct {
+	value    T       // The value of the item; arbitrary.
+	priority float64 // The priority of the item in the queue.
+	g, h     float64 // Cost so far and estimate to go, used to break ties
+	order    int     // Number of items pushed before this one
+	random   float64 // Random key for RandomTie
+	index    int     // The index of the item in the heap, or in its bucket.
+	bucket   int     // The bucket holding the item in a BucketQueue or RadixHeap.
+	key      uint64  // The priority in whole resolution steps in a BucketQueue or RadixHeap.
 }
 
-// A PriorityQueue implements heap.Interface and holds Items.
-type PriorityQueue []*Item
+// TieBreak decides which of two items with the same priority is popped first
+type TieBreak int
 
-func (pq PriorityQueue) Len() int { return len(pq) }
+const (
+	AnyTie    TieBreak = iota // Whichever the heap happens to hold first
+	LargerG                   // The item farthest from the start, A* then dives towards the goal
+	SmallerH                  // The item estimated closest to the goal, same as LargerG when priority is g + h
+	FirstIn                   // The item pushed first, equal plateaus are expanded breadth first
+	LastIn                    // The item pushed last, equal plateaus are expanded depth first
+	RandomTie                 // A random item, reproducible from the seed
+)
+
+// tieEpsilon is how close two priorities have to be to count as a tie, sums of diagonal steps rarely match exactly
+const tieEpsilon = 1e-9
 
-func (pq PriorityQueue) Less(i, j int) bool {
-	return pq[i].priority < pq[j].priority // The less function compares fScores to determine priority
+// A PriorityQueue is a binary heap of Items. Items are indexed by value, so Contains and Update do not have to
+// scan the heap. A value is meant to be queued once, Update changes its priority.
+type PriorityQueue[T comparable] struct {
+	items    []*Item[T]
+	indexed  map[T]*Item[T]
+	tieBreak TieBreak
+	seed     int64
+	rng      *rand.Rand
+	pushed   int
 }
 
-func (pq PriorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
+// itemHeap implements heap.Interface for a PriorityQueue, keeping the untyped Push and Pop out of its API
+type itemHeap[T comparable] PriorityQueue[T]
+
+func (h *itemHeap[T]) Len() int { return len(h.items) }
+
+func (h *itemHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	if math.Abs(a.priority-b.priority) > tieEpsilon {
+		return a.priority < b.priority // The less function compares fScores to determine priority
+	}
+	switch h.tieBreak {
+	case LargerG:
+		return a.g > b.g
+	case SmallerH:
+		return a.h < b.h
+	case FirstIn:
+		return a.order < b.order
+	case LastIn:
+		return a.order > b.order
+	case RandomTie:
+		return a.random < b.random
+	}
+	return false
 }
 
-func (pq *PriorityQueue) Push(x interface{}) {
-	n := len(*pq)
-	item := x.(*Item)
-	item.index = n
-	*pq = append(*pq, item)
+func (h *itemHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
 }
 
-func (pq *PriorityQueue) Pop() interface{} {
-	old := *pq
-	n := len(old)
-	item := old[n-1]
-	old[n-1] = nil  // avoid memory leak
+func (h *itemHeap[T]) Push(x interface{}) {
+	item := x.(*Item[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *itemHeap[T]) Pop() interface{} {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil // avoid memory leak
+	h.items = h.items[:n-1]
 	item.index = -1 // for safety
-	*pq = old[0 : n-1]
 	return item
 }
 
-// Update modifies the priority and reorders the queue.
-func (pq *PriorityQueue) Update(node *models.Node, newPriority float64) {
-	for i, item := range *pq {
-		if item.node == node {
-			(*pq)[i].priority = newPriority
-			heap.Fix(pq, i)
-			return
-		}
+func (pq *PriorityQueue[T]) Len() int { return len(pq.items) }
+
+// Insert adds the item to the queue in O(log n)
+func (pq *PriorityQueue[T]) Insert(item *Item[T]) {
+	item.order = pq.pushed
+	pq.pushed++
+	if pq.tieBreak == RandomTie {
+		item.random = pq.rng.Float64()
 	}
+	if pq.indexed == nil {
+		pq.indexed = make(map[T]*Item[T])
+	}
+	pq.indexed[item.value] = item
+	heap.Push((*itemHeap[T])(pq), item)
 }
 
-func (pq PriorityQueue) Contains(node *models.Node) bool {
-	for _, item := range pq {
-		if item.node == node {
-			return true
-		}
+// PopMin removes and returns the item with the lowest priority in O(log n)
+func (pq *PriorityQueue[T]) PopMin() *Item[T] {
+	item := heap.Pop((*itemHeap[T])(pq)).(*Item[T])
+	// A value inserted twice stays indexed by its later item
+	if pq.indexed[item.value] == item {
+		delete(pq.indexed, item.value)
 	}
-	return false
+	return item
+}
+
+// Update modifies the priority and reorders the queue in O(log n), values not in the queue are ignored.
+func (pq *PriorityQueue[T]) Update(value T, newPriority float64) {
+	item, ok := pq.indexed[value]
+	if !ok {
+		return
+	}
+	item.priority = newPriority
+	heap.Fix((*itemHeap[T])(pq), item.index)
+}
+
+// UpdateScored modifies the priority and the tie breaking costs, see NewScoredItem
+func (pq *PriorityQueue[T]) UpdateScored(value T, newPriority, g, h float64) {
+	item, ok := pq.indexed[value]
+	if !ok {
+		return
+	}
+	item.priority, item.g, item.h = newPriority, g, h
+	heap.Fix((*itemHeap[T])(pq), item.index)
+}
+
+// Contains reports in O(1) whether value is in the queue
+func (pq *PriorityQueue[T]) Contains(value T) bool {
+	_, ok := pq.indexed[value]
+	return ok
 }
 
 // NewItem creates a new Item.
-func NewItem(node *models.Node, priority float64) *Item {
-	return &Item{
-		node:     node,
+func NewItem[T comparable](value T, priority float64) *Item[T] {
+	return &Item[T]{
+		value:    value,
+		priority: priority,
+	}
+}
+
+// NewScoredItem creates a new Item that also carries its cost from the start g and its estimate to the goal h,
+// for the LargerG and SmallerH tie breaks
+func NewScoredItem[T comparable](value T, priority, g, h float64) *Item[T] {
+	return &Item[T]{
+		value:    value,
 		priority: priority,
+		g:        g,
+		h:        h,
 	}
 }
 
-func (item *Item) GetNode() *models.Node {
-	return item.node
+func (item *Item[T]) GetValue() T {
+	return item.value
 }
 
-func (item *Item) GetPriority() float64 {
+func (item *Item[T]) GetPriority() float64 {
 	return item.priority
 }
 
-// Init initializes or clears the priority queue.
-func (pq *PriorityQueue) Init() {
-	heap.Init(pq)
+// Init initializes or clears the priority queue. The tie break is kept and random ties start over from the seed.
+func (pq *PriorityQueue[T]) Init() {
+	pq.items = nil
+	pq.indexed = make(map[T]*Item[T])
+	pq.pushed = 0
+	pq.rng = rand.New(rand.NewSource(pq.seed))
+}
+
+// SetTieBreak selects how items with the same priority are ordered, seed is only used by RandomTie.
+// It has to be set while the queue is empty.
+func (pq *PriorityQueue[T]) SetTieBreak(tieBreak TieBreak, seed int64) error {
+	if tieBreak < AnyTie || tieBreak > RandomTie {
+		return errors.New("tie break not found")
+	}
+	if len(pq.items) > 0 {
+		return errors.New("tie break can only be changed on an empty queue")
+	}
+	pq.tieBreak, pq.seed = tieBreak, seed
+	pq.rng = rand.New(rand.NewSource(seed))
+	return nil
 }
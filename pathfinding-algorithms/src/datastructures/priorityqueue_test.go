@@ -0,0 +1,146 @@
+package datastructures_test
+
+import (
+	"fmt"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+	"testing"
+)
+
+func TestPriorityQueueUpdateReorders(t *testing.T) {
+	nodes := []*models.Node{{X: 0}, {X: 1}, {X: 2}, {X: 3}}
+	pq := &datastructures.PriorityQueue[*models.Node]{}
+	pq.Init()
+	for i, node := range nodes {
+		pq.Insert(datastructures.NewItem(node, float64(10+i)))
+	}
+	pq.Update(nodes[3], 1)
+	pq.Update(nodes[0], 20)
+	pq.Update(&models.Node{X: 9}, 0) // Not queued, ignored
+
+	var order []int
+	for pq.Len() > 0 {
+		node := pq.PopMin().GetValue()
+		if pq.Contains(node) {
+			t.Fatalf("Popped node %d is still contained", node.X)
+		}
+		order = append(order, node.X)
+	}
+	if fmt.Sprint(order) != "[3 1 2 0]" {
+		t.Fatalf("Expected order [3 1 2 0], got %v", order)
+	}
+}
+
+func TestPriorityQueueTieBreaks(t *testing.T) {
+	popOrder := func(tieBreak datastructures.TieBreak) string {
+		pq := &datastructures.PriorityQueue[*models.Node]{}
+		if err := pq.SetTieBreak(tieBreak, 11); err != nil {
+			t.Fatalf("SetTieBreak failed: %v", err)
+		}
+		pq.Init()
+		// Equal priorities, g rises and h falls with X
+		for x := 0; x < 5; x++ {
+			pq.Insert(datastructures.NewScoredItem(&models.Node{X: x}, 10, float64(x), float64(10-x)))
+		}
+		var order []int
+		for pq.Len() > 0 {
+			order = append(order, pq.PopMin().GetValue().X)
+		}
+		return fmt.Sprint(order)
+	}
+	for tieBreak, want := range map[datastructures.TieBreak]string{
+		datastructures.LargerG:  "[4 3 2 1 0]",
+		datastructures.SmallerH: "[4 3 2 1 0]",
+		datastructures.FirstIn:  "[0 1 2 3 4]",
+		datastructures.LastIn:   "[4 3 2 1 0]",
+	} {
+		if got := popOrder(tieBreak); got != want {
+			t.Fatalf("Tie break %d: expected %s, got %s", tieBreak, want, got)
+		}
+	}
+	if popOrder(datastructures.RandomTie) != popOrder(datastructures.RandomTie) {
+		t.Fatalf("Random ties differ for the same seed")
+	}
+}
+
+// dijkstra searches the whole grid from its start with the open set, the way the algorithms use it
+func dijkstra(b *testing.B, grid *models.Grid, openSet datastructures.OpenSet[*models.Node]) {
+	start, _ := grid.GetStart()
+	distances := map[*models.Node]float64{start: 0}
+	closed := make(map[*models.Node]bool)
+	openSet.Init()
+	openSet.Insert(datastructures.NewItem(start, 0))
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		closed[current] = true
+		neighbors, err := grid.GetNeighbors(current)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, neighbor := range neighbors {
+			if neighbor.IsWall || closed[neighbor] {
+				continue
+			}
+			distance := distances[current] + (current.Cost()+neighbor.Cost())/2
+			if d, ok := distances[neighbor]; ok && distance >= d {
+				continue
+			}
+			distances[neighbor] = distance
+			if !openSet.Contains(neighbor) {
+				openSet.Insert(datastructures.NewItem(neighbor, distance))
+			} else {
+				openSet.Update(neighbor, distance)
+			}
+		}
+	}
+}
+
+// BenchmarkPriorityQueueDijkstra floods open grids of growing size, the time per node should grow
+// logarithmically rather than linearly with the size of the grid
+func BenchmarkPriorityQueueDijkstra(b *testing.B) {
+	for _, side := range []int{50, 100, 250, 500} {
+		grid, err := models.NewGrid(side, side)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%dx%d", side, side), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				dijkstra(b, grid, &datastructures.PriorityQueue[*models.Node]{})
+			}
+		})
+	}
+}
+
+// BenchmarkPriorityQueueUpdate lowers priorities in a queue holding every node of a 500x500 grid
+func BenchmarkPriorityQueueUpdate(b *testing.B) {
+	const side = 500
+	nodes := make([]*models.Node, side*side)
+	pq := &datastructures.PriorityQueue[*models.Node]{}
+	pq.Init()
+	for i := range nodes {
+		nodes[i] = &models.Node{X: i % side, Y: i / side}
+		pq.Insert(datastructures.NewItem(nodes[i], float64(len(nodes)+i)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pq.Update(nodes[(i*7919)%len(nodes)], float64(len(nodes)-i))
+	}
+}
+
+// BenchmarkPriorityQueueContains looks up nodes in a queue holding half of a 500x500 grid
+func BenchmarkPriorityQueueContains(b *testing.B) {
+	const side = 500
+	nodes := make([]*models.Node, side*side)
+	pq := &datastructures.PriorityQueue[*models.Node]{}
+	pq.Init()
+	for i := range nodes {
+		nodes[i] = &models.Node{X: i % side, Y: i / side}
+		if i%2 == 0 {
+			pq.Insert(datastructures.NewItem(nodes[i], float64(i)))
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pq.Contains(nodes[(i*7919)%len(nodes)])
+	}
+}
@@ -1,29 +1,61 @@
 package datastructures
 
-type Queue []interface{}
+// Queue is a first in, first out queue of T backed by a Deque, so Enqueue and Dequeue are amortized O(1) and
+// dequeued items, such as snapshots that have been shown, can be garbage collected right away.
+// The zero value is an empty queue.
+type Queue[T any] struct {
+	items Deque[T]
+}
+
+// NewQueue returns an empty queue with room for capacity items, see Reserve
+func NewQueue[T any](capacity int) *Queue[T] {
+	q := &Queue[T]{}
+	q.Reserve(capacity)
+	return q
+}
 
 // Enqueue adds an item to the end of the queue
-func (q *Queue) Enqueue(item interface{}) {
-	*q = append(*q, item)
+func (q *Queue[T]) Enqueue(item T) {
+	q.items.PushBack(item)
 }
 
 // Dequeue removes an item from the start of the queue and returns it
-// Returns nil if the queue is empty
-func (q *Queue) Dequeue() interface{} {
-	if q.IsEmpty() {
-		return nil
-	}
-	item := (*q)[0]
-	*q = (*q)[1:]
-	return item
+// Returns the zero value and false if the queue is empty
+func (q *Queue[T]) Dequeue() (T, bool) {
+	return q.items.PopFront()
+}
+
+// Peek returns the item at the start of the queue without removing it
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.items.PeekFront()
 }
 
 // IsEmpty checks if the queue is empty
-func (q *Queue) IsEmpty() bool {
-	return len(*q) == 0
+func (q *Queue[T]) IsEmpty() bool {
+	return q.items.IsEmpty()
 }
 
 // Size returns the number of items in the queue
-func (q *Queue) Size() int {
-	return len(*q)
+func (q *Queue[T]) Size() int {
+	return q.items.Size()
+}
+
+// Cap returns the number of items the queue holds before it has to grow
+func (q *Queue[T]) Cap() int {
+	return q.items.Cap()
+}
+
+// Reserve makes room for capacity items and keeps the queue from shrinking below it, 0 removes the reservation
+func (q *Queue[T]) Reserve(capacity int) {
+	q.items.Reserve(capacity)
+}
+
+// Clip releases all memory not needed for the items in the queue
+func (q *Queue[T]) Clip() {
+	q.items.Clip()
+}
+
+// Clear removes every item, keeping only the reserved capacity
+func (q *Queue[T]) Clear() {
+	q.items.Clear()
 }
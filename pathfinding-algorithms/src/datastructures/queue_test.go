@@ -0,0 +1,94 @@
+package datastructures_test
+
+import (
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// snapshot builds a snapshot like the algorithms queue, finalized is counted up once its nodes are collected
+func snapshot(leg int, finalized *atomic.Int64) models.Snapshot {
+	nodes := make([][]*models.Node, 4)
+	for y := range nodes {
+		nodes[y] = make([]*models.Node, 4)
+		for x := range nodes[y] {
+			nodes[y][x] = &models.Node{X: x, Y: y}
+		}
+	}
+	runtime.SetFinalizer(nodes[0][0], func(*models.Node) { finalized.Add(1) })
+	return models.Snapshot{Leg: leg, Nodes: nodes}
+}
+
+// waitFinalized collects garbage until want snapshots are finalized or a second has passed
+func waitFinalized(finalized *atomic.Int64, want int64) int64 {
+	deadline := time.Now().Add(time.Second)
+	for finalized.Load() < want && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	return finalized.Load()
+}
+
+func TestDequeuedSnapshotsAreCollectable(t *testing.T) {
+	const count = 100
+	var finalized atomic.Int64
+	queue := &datastructures.Queue[models.Snapshot]{}
+	for i := 0; i < count; i++ {
+		queue.Enqueue(snapshot(i, &finalized))
+	}
+	for i := 0; i < count/2; i++ {
+		if s, ok := queue.Dequeue(); !ok || s.Leg != i {
+			t.Fatalf("Dequeued leg %d, %v, expected %d", s.Leg, ok, i)
+		}
+	}
+
+	if got := waitFinalized(&finalized, count/2); got != count/2 {
+		t.Fatalf("%d of %d dequeued snapshots were collected", got, count/2)
+	}
+	// The queue is still in use, the snapshots left in it must stay alive
+	if queue.Size() != count/2 {
+		t.Fatalf("Queue holds %d snapshots, expected %d", queue.Size(), count/2)
+	}
+	if next, _ := queue.Peek(); next.Leg != count/2 {
+		t.Fatalf("Next snapshot is leg %d, expected %d", next.Leg, count/2)
+	}
+	runtime.KeepAlive(queue)
+}
+
+func TestQueueCapacity(t *testing.T) {
+	queue := datastructures.NewQueue[int](100)
+	if queue.Cap() < 100 {
+		t.Fatalf("Reserved 100, capacity is %d", queue.Cap())
+	}
+	// Draining does not shrink below the reservation
+	for i := 0; i < 100; i++ {
+		queue.Enqueue(i)
+	}
+	for !queue.IsEmpty() {
+		queue.Dequeue()
+	}
+	if queue.Cap() < 100 {
+		t.Fatalf("Capacity shrank below the reservation to %d", queue.Cap())
+	}
+
+	// Without a reservation a drained burst is released
+	queue.Reserve(0)
+	for i := 0; i < 10000; i++ {
+		queue.Enqueue(i)
+	}
+	for i := 0; i < 9990; i++ {
+		if item, _ := queue.Dequeue(); item != i {
+			t.Fatalf("Dequeued %d, expected %d", item, i)
+		}
+	}
+	if queue.Cap() > 64 {
+		t.Fatalf("Capacity of %d kept after draining to %d items", queue.Cap(), queue.Size())
+	}
+	queue.Clip()
+	if queue.Cap() != queue.Size() {
+		t.Fatalf("Clip left capacity %d for %d items", queue.Cap(), queue.Size())
+	}
+}
@@ -0,0 +1,94 @@
+package datastructures
+
+import "math/bits"
+
+// RadixHeap is a monotone priority queue for integer keys. Bucket i holds the keys that first differ from the last
+// popped key in bit i, so popping only has to redistribute the lowest non-empty bucket, and each item moves down at
+// most 64 times. Like the BucketQueue it needs priorities popped not to decrease and counts priorities within one
+// resolution step as equal, but it does not keep a bucket for every step up to the largest priority.
+type RadixHeap[T comparable] struct {
+	resolution float64
+	buckets    [65][]*Item[T]
+	last       uint64 // Key popped last, no key in the heap is lower
+	indexed    map[T]*Item[T]
+}
+
+func NewRadixHeap[T comparable](resolution float64) *RadixHeap[T] {
+	return &RadixHeap[T]{resolution: resolution}
+}
+
+// Init initializes or clears the radix heap
+func (r *RadixHeap[T]) Init() {
+	for i := range r.buckets {
+		r.buckets[i] = nil
+	}
+	r.last = 0
+	r.indexed = make(map[T]*Item[T])
+}
+
+func (r *RadixHeap[T]) Len() int { return len(r.indexed) }
+
+func (r *RadixHeap[T]) Insert(item *Item[T]) {
+	r.indexed[item.value] = item
+	item.key = monotoneKey(item.priority, r.resolution, r.last)
+	r.place(item)
+}
+
+func (r *RadixHeap[T]) place(item *Item[T]) {
+	item.bucket = bits.Len64(item.key ^ r.last)
+	item.index = len(r.buckets[item.bucket])
+	r.buckets[item.bucket] = append(r.buckets[item.bucket], item)
+}
+
+// PopMin removes and returns an item with the lowest key, nil if the heap is empty
+func (r *RadixHeap[T]) PopMin() *Item[T] {
+	if len(r.indexed) == 0 {
+		return nil
+	}
+	if len(r.buckets[0]) == 0 {
+		i := 1
+		for len(r.buckets[i]) == 0 {
+			i++
+		}
+		// The lowest key of the bucket becomes the last key, all its items now differ from it in lower bits
+		bucket := r.buckets[i]
+		r.buckets[i] = nil
+		r.last = bucket[0].key
+		for _, item := range bucket[1:] {
+			r.last = min(r.last, item.key)
+		}
+		for _, item := range bucket {
+			r.place(item)
+		}
+	}
+	bucket := &r.buckets[0]
+	item := (*bucket)[len(*bucket)-1]
+	removeFromBucket(bucket, item)
+	delete(r.indexed, item.value)
+	return item
+}
+
+func (r *RadixHeap[T]) Contains(value T) bool {
+	_, ok := r.indexed[value]
+	return ok
+}
+
+// Update moves the item to the bucket of its new key
+func (r *RadixHeap[T]) Update(value T, newPriority float64) {
+	item, ok := r.indexed[value]
+	if !ok {
+		return
+	}
+	removeFromBucket(&r.buckets[item.bucket], item)
+	item.priority = newPriority
+	item.key = monotoneKey(newPriority, r.resolution, r.last)
+	r.place(item)
+}
+
+// UpdateScored updates the priority, g and h only matter for tie breaking in a PriorityQueue
+func (r *RadixHeap[T]) UpdateScored(value T, newPriority, g, h float64) {
+	if item, ok := r.indexed[value]; ok {
+		item.g, item.h = g, h
+	}
+	r.Update(value, newPriority)
+}
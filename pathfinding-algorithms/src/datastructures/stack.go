@@ -0,0 +1,41 @@
+package datastructures
+
+// Stack is a last in, first out stack of T
+type Stack[T any] []T
+
+// Push adds an item to the top of the stack
+func (s *Stack[T]) Push(item T) {
+	*s = append(*s, item)
+}
+
+// Pop removes the item on top of the stack and returns it
+// Returns the zero value and false if the stack is empty
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if s.IsEmpty() {
+		return zero, false
+	}
+	item := (*s)[len(*s)-1]
+	(*s)[len(*s)-1] = zero // avoid memory leak
+	*s = (*s)[:len(*s)-1]
+	return item, true
+}
+
+// Peek returns the item on top of the stack without removing it
+func (s *Stack[T]) Peek() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+	return (*s)[len(*s)-1], true
+}
+
+// IsEmpty checks if the stack is empty
+func (s *Stack[T]) IsEmpty() bool {
+	return len(*s) == 0
+}
+
+// Size returns the number of items on the stack
+func (s *Stack[T]) Size() int {
+	return len(*s)
+}
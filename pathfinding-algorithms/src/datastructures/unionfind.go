@@ -0,0 +1,41 @@
+package datastructures
+
+// UnionFind tracks a partition of the elements 0..n-1 into disjoint sets
+type UnionFind struct {
+	parents []int
+	ranks   []int
+}
+
+// NewUnionFind creates n singleton sets
+func NewUnionFind(n int) *UnionFind {
+	u := &UnionFind{parents: make([]int, n), ranks: make([]int, n)}
+	for i := range u.parents {
+		u.parents[i] = i
+	}
+	return u
+}
+
+// Find returns the representative of the set containing x
+func (u *UnionFind) Find(x int) int {
+	for u.parents[x] != x {
+		u.parents[x] = u.parents[u.parents[x]] // Path halving
+		x = u.parents[x]
+	}
+	return x
+}
+
+// Union merges the sets of a and b, it returns false if they were already the same set
+func (u *UnionFind) Union(a, b int) bool {
+	rootA, rootB := u.Find(a), u.Find(b)
+	if rootA == rootB {
+		return false
+	}
+	if u.ranks[rootA] < u.ranks[rootB] {
+		rootA, rootB = rootB, rootA
+	}
+	u.parents[rootB] = rootA
+	if u.ranks[rootA] == u.ranks[rootB] {
+		u.ranks[rootA]++
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// AldousBroder builds a uniform spanning tree with the Aldous-Broder algorithm. A single random walk
+// wanders the grid and carves into every cell it enters for the first time. It is unbiased like Wilson's
+// but slow, the walk revisits cells many times before covering the grid.
+type AldousBroder struct{}
+
+func (a *AldousBroder) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	inMaze := make([]bool, l.size())
+	current := l.cellAt(rng.Intn(l.size()))
+	inMaze[l.index(current)] = true
+	l.open(current)
+
+	for remaining := l.size() - 1; remaining > 0; {
+		neighbors := l.neighbors(current)
+		next := neighbors[rng.Intn(len(neighbors))]
+		if !inMaze[l.index(next)] {
+			inMaze[l.index(next)] = true
+			l.connect(current, next)
+			remaining--
+		}
+		current = next
+	}
+	return l.finish()
+}
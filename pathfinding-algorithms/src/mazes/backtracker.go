@@ -0,0 +1,46 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Backtracker carves a perfect maze with a randomized depth-first search. It follows one passage as far as
+// it goes before backing up, which leaves long winding corridors and few dead ends.
+type Backtracker struct{}
+
+func (b *Backtracker) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	visited := make([]bool, l.size())
+	first := l.cellAt(rng.Intn(l.size()))
+	visited[l.index(first)] = true
+	l.open(first)
+
+	// An explicit stack instead of recursion, large grids would need a path as deep as the whole maze
+	stack := []cell{first}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		var unvisited []cell
+		for _, neighbor := range l.neighbors(current) {
+			if !visited[l.index(neighbor)] {
+				unvisited = append(unvisited, neighbor)
+			}
+		}
+		if len(unvisited) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := unvisited[rng.Intn(len(unvisited))]
+		visited[l.index(next)] = true
+		l.connect(current, next)
+		stack = append(stack, next)
+	}
+	return l.finish()
+}
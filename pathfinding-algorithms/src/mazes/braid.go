@@ -0,0 +1,81 @@
+package mazes
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Braid removes the given fraction of dead ends by knocking down a wall at each, turning a perfect maze into
+// one with loops and competing routes. A dead end is joined to another dead end when it can be, which
+// removes two at once.
+func Braid(grid *models.Grid, rng *rand.Rand, fraction float64) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	if fraction < 0 || fraction > 1 {
+		return errors.New("braid fraction must be between 0 and 1")
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	var deadEnds []*models.Node
+	for _, row := range nodes {
+		for _, node := range row {
+			if isDeadEnd(nodes, node) {
+				deadEnds = append(deadEnds, node)
+			}
+		}
+	}
+	rng.Shuffle(len(deadEnds), func(i, j int) {
+		deadEnds[i], deadEnds[j] = deadEnds[j], deadEnds[i]
+	})
+
+	for _, node := range deadEnds[:int(math.Round(fraction*float64(len(deadEnds))))] {
+		// Earlier removals may already have opened this one up
+		if !isDeadEnd(nodes, node) {
+			continue
+		}
+		var walls, towardsDeadEnds []*models.Node
+		for _, d := range orthogonal {
+			wall, beyond := nodeAt(nodes, node.X+d.Dx, node.Y+d.Dy), nodeAt(nodes, node.X+2*d.Dx, node.Y+2*d.Dy)
+			if wall == nil || beyond == nil || !wall.IsWall || beyond.IsWall {
+				continue
+			}
+			walls = append(walls, wall)
+			if isDeadEnd(nodes, beyond) {
+				towardsDeadEnds = append(towardsDeadEnds, wall)
+			}
+		}
+		if len(towardsDeadEnds) > 0 {
+			walls = towardsDeadEnds
+		}
+		if len(walls) > 0 {
+			walls[rng.Intn(len(walls))].IsWall = false
+		}
+	}
+	return nil
+}
+
+// isDeadEnd reports whether node is open with a single open neighbour
+func isDeadEnd(nodes [][]*models.Node, node *models.Node) bool {
+	if node.IsWall {
+		return false
+	}
+	open := 0
+	for _, d := range orthogonal {
+		if neighbor := nodeAt(nodes, node.X+d.Dx, node.Y+d.Dy); neighbor != nil && !neighbor.IsWall {
+			open++
+		}
+	}
+	return open == 1
+}
+
+func nodeAt(nodes [][]*models.Node, x, y int) *models.Node {
+	if y < 0 || y >= len(nodes) || x < 0 || x >= len(nodes[y]) {
+		return nil
+	}
+	return nodes[y][x]
+}
@@ -0,0 +1,86 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Cave grows organic caves with a cellular automaton. The grid is filled with random walls, then smoothed:
+// an open node turns into a wall when at least BirthLimit of its eight neighbours are walls, and a wall
+// survives when at least SurvivalLimit are. Outside the grid counts as wall. Afterwards the end and the
+// waypoints are tunnelled to the cave of the start if they ended up in another one.
+type Cave struct {
+	FillProbability float64 // Chance of a node starting out as a wall
+	Iterations      int
+	BirthLimit      int
+	SurvivalLimit   int
+}
+
+// NewCave returns a cave generator with settings that give large, well connected caves
+func NewCave() *Cave {
+	return &Cave{FillProbability: 0.45, Iterations: 5, BirthLimit: 5, SurvivalLimit: 4}
+}
+
+func (c *Cave) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	if c.FillProbability < 0 || c.FillProbability > 1 {
+		return errors.New("fill probability must be between 0 and 1")
+	}
+	if c.BirthLimit < 0 || c.BirthLimit > 8 || c.SurvivalLimit < 0 || c.SurvivalLimit > 8 {
+		return errors.New("birth and survival limits must be between 0 and 8")
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	walls := make([][]bool, len(nodes))
+	for y, row := range nodes {
+		walls[y] = make([]bool, len(row))
+		for x, node := range row {
+			walls[y][x] = !isFixed(node) && rng.Float64() < c.FillProbability
+		}
+	}
+
+	for i := 0; i < c.Iterations; i++ {
+		smoothed := make([][]bool, len(walls))
+		for y, row := range walls {
+			smoothed[y] = make([]bool, len(row))
+			for x, wall := range row {
+				if isFixed(nodes[y][x]) {
+					continue
+				}
+				neighbors := c.wallNeighbors(walls, x, y)
+				smoothed[y][x] = wall && neighbors >= c.SurvivalLimit || !wall && neighbors >= c.BirthLimit
+			}
+		}
+		walls = smoothed
+	}
+
+	for y, row := range nodes {
+		for x, node := range row {
+			node.IsWall = walls[y][x]
+			node.Weight = 0
+		}
+	}
+	return connectStops(grid)
+}
+
+// wallNeighbors counts the walls among the eight neighbours of (x, y)
+func (c *Cave) wallNeighbors(walls [][]bool, x, y int) int {
+	count := 0
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			nx, ny := x+dx, y+dy
+			if nx < 0 || ny < 0 || ny >= len(walls) || nx >= len(walls[ny]) || walls[ny][nx] {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,119 @@
+package mazes
+
+import (
+	"errors"
+	"pathfinding-algorithms/models"
+)
+
+// connectStops carves the fewest walls needed to join every end and waypoint to the open region of the start
+func connectStops(grid *models.Grid) error {
+	start, err := grid.GetStart()
+	if err != nil {
+		return err
+	}
+	ends, err := grid.GetEnds()
+	if err != nil {
+		return err
+	}
+	waypoints, err := grid.GetWaypoints()
+	if err != nil {
+		return err
+	}
+	reached := make([][]bool, grid.GetHeight())
+	for y := range reached {
+		reached[y] = make([]bool, grid.GetWidth())
+	}
+	if err := flood(grid, reached, start); err != nil {
+		return err
+	}
+	for _, stop := range append(append([]*models.Node{}, ends...), waypoints...) {
+		if reached[stop.Y][stop.X] {
+			continue
+		}
+		tunnel, err := cheapestTunnel(grid, reached, stop)
+		if err != nil {
+			return err
+		}
+		for _, node := range tunnel {
+			node.IsWall = false
+		}
+		if err := flood(grid, reached, stop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flood marks every open node connected to from as reached
+func flood(grid *models.Grid, reached [][]bool, from *models.Node) error {
+	reached[from.Y][from.X] = true
+	stack := []*models.Node{from}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		neighbors, err := grid.GetNeighbors(current)
+		if err != nil {
+			return err
+		}
+		for _, neighbor := range neighbors {
+			if !neighbor.IsWall && !reached[neighbor.Y][neighbor.X] {
+				reached[neighbor.Y][neighbor.X] = true
+				stack = append(stack, neighbor)
+			}
+		}
+	}
+	return nil
+}
+
+// cheapestTunnel finds the nodes from node to a reached one that cross the fewest walls. It is a breadth-first
+// search in layers, open nodes join the layer being searched and walls the next one.
+func cheapestTunnel(grid *models.Grid, reached [][]bool, node *models.Node) ([]*models.Node, error) {
+	costs := make([][]int, grid.GetHeight())
+	for y := range costs {
+		costs[y] = make([]int, grid.GetWidth())
+		for x := range costs[y] {
+			costs[y][x] = -1
+		}
+	}
+	parents := make(map[*models.Node]*models.Node)
+	costs[node.Y][node.X] = 0
+	layer, next := []*models.Node{node}, []*models.Node(nil)
+	for walls := 0; len(layer) > 0; walls++ {
+		for i := 0; i < len(layer); i++ {
+			current := layer[i]
+			// Nodes reached through fewer walls after being queued are searched in their own layer
+			if costs[current.Y][current.X] != walls {
+				continue
+			}
+			if reached[current.Y][current.X] {
+				var tunnel []*models.Node
+				for ; current != nil; current = parents[current] {
+					tunnel = append(tunnel, current)
+				}
+				return tunnel, nil
+			}
+			neighbors, err := grid.GetNeighbors(current)
+			if err != nil {
+				return nil, err
+			}
+			for _, neighbor := range neighbors {
+				cost := walls
+				if neighbor.IsWall {
+					cost++
+				}
+				if known := costs[neighbor.Y][neighbor.X]; known >= 0 && known <= cost {
+					continue
+				}
+				costs[neighbor.Y][neighbor.X] = cost
+				parents[neighbor] = current
+				if neighbor.IsWall {
+					next = append(next, neighbor)
+				} else {
+					layer = append(layer, neighbor)
+				}
+			}
+		}
+		layer, next = next, nil
+	}
+	return nil, errors.New("no open region to connect to")
+}
@@ -0,0 +1,103 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Division builds a perfect maze with recursive division. Starting from an open grid, every region is split
+// by a wall with a single gap, until the regions are one cell wide. Unlike the carving generators it adds
+// walls, which gives long straight walls and a visible rectangular structure.
+type Division struct {
+	recorder Recorder
+}
+
+func (d *Division) SetRecorder(recorder Recorder) {
+	d.recorder = recorder
+}
+
+// region is a rectangle of cells or nodes, both corners inclusive
+type region struct {
+	x0, y0, x1, y1 int
+}
+
+func (r region) center() (int, int) {
+	return (r.x0 + r.x1) / 2, (r.y0 + r.y1) / 2
+}
+
+func (d *Division) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	// Open the lattice, rows and columns left over on even sized grids stay walls like in the carved mazes
+	for y := 0; y < 2*l.height-1; y++ {
+		for x := 0; x < 2*l.width-1; x++ {
+			l.maze[y][x].IsWall = false
+		}
+	}
+
+	recordInterval, walls := grid.SnapshotInterval(), 0
+	if err := d.record(); err != nil {
+		return err
+	}
+
+	regions := []region{{0, 0, l.width - 1, l.height - 1}}
+	for len(regions) > 0 {
+		r := regions[len(regions)-1]
+		regions = regions[:len(regions)-1]
+		width, height := r.x1-r.x0+1, r.y1-r.y0+1
+		if width < 2 && height < 2 {
+			continue
+		}
+
+		// Split across the longer side, so regions stay roughly square
+		horizontal := height > width || height == width && rng.Intn(2) == 0
+		if horizontal {
+			// The wall lies between cell rows at and at+1, the gap is in one cell column
+			at, gap := r.y0+rng.Intn(height-1), r.x0+rng.Intn(width)
+			for x := 2 * r.x0; x <= 2*r.x1; x++ {
+				if x != 2*gap {
+					d.addWall(l.maze[2*at+1][x])
+				}
+			}
+			regions = append(regions, region{r.x0, r.y0, r.x1, at}, region{r.x0, at + 1, r.x1, r.y1})
+		} else {
+			at, gap := r.x0+rng.Intn(width-1), r.y0+rng.Intn(height)
+			for y := 2 * r.y0; y <= 2*r.y1; y++ {
+				if y != 2*gap {
+					d.addWall(l.maze[y][2*at+1])
+				}
+			}
+			regions = append(regions, region{r.x0, r.y0, at, r.y1}, region{at + 1, r.y0, r.x1, r.y1})
+		}
+
+		walls++
+		if walls%recordInterval == 0 {
+			if err := d.record(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := l.finish(); err != nil {
+		return err
+	}
+	return d.record()
+}
+
+func (d *Division) addWall(node *models.Node) {
+	if !isFixed(node) {
+		node.IsWall = true
+	}
+}
+
+func (d *Division) record() error {
+	if d.recorder == nil {
+		return nil
+	}
+	return d.recorder.Record()
+}
@@ -0,0 +1,221 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Dungeon lays out rooms and corridors with binary space partitioning. The grid is split recursively into
+// leaves, every leaf gets a room, and the rooms of the two halves of every split are joined by a corridor.
+// The start and the end are moved into rooms far apart from each other.
+type Dungeon struct {
+	MinLeafSize int // Smallest side of a leaf, its room is at least one node smaller on every side
+	MaxLeafSize int // Leaves with a longer side are always split
+	DoorWeight  int // Weight of the nodes where corridors enter rooms, 0 leaves plain doorways
+}
+
+// NewDungeon returns a dungeon generator with small rooms and doors that are costly to pass
+func NewDungeon() *Dungeon {
+	return &Dungeon{MinLeafSize: 5, MaxLeafSize: 16, DoorWeight: 5}
+}
+
+func (d *Dungeon) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	if d.MinLeafSize < 3 || d.MaxLeafSize < d.MinLeafSize {
+		return errors.New("leaves must be at least 3 wide and the maximum not below the minimum")
+	}
+	if d.DoorWeight < 0 {
+		return errors.New("door weight must not be negative")
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	var rooms []region
+	var corridors [][]*models.Node
+	d.split(nodes, rng, region{0, 0, grid.GetWidth() - 1, grid.GetHeight() - 1}, true, &rooms, &corridors)
+	if len(rooms) < 2 {
+		return errors.New("grid is too small for two rooms")
+	}
+	if err := d.placeStops(grid, rooms); err != nil {
+		return err
+	}
+
+	if err := fillWalls(grid); err != nil {
+		return err
+	}
+	inRoom := make([][]bool, len(nodes))
+	for y := range inRoom {
+		inRoom[y] = make([]bool, len(nodes[y]))
+	}
+	for _, r := range rooms {
+		for y := r.y0; y <= r.y1; y++ {
+			for x := r.x0; x <= r.x1; x++ {
+				nodes[y][x].IsWall = false
+				inRoom[y][x] = true
+			}
+		}
+	}
+	for _, corridor := range corridors {
+		for i, node := range corridor {
+			if inRoom[node.Y][node.X] || !node.IsWall {
+				continue
+			}
+			node.IsWall = false
+			// A door is where the corridor leaves the room it comes from or enters the next one
+			entersRoom := i > 0 && inRoom[corridor[i-1].Y][corridor[i-1].X] ||
+				i < len(corridor)-1 && inRoom[corridor[i+1].Y][corridor[i+1].X]
+			if entersRoom && !isFixed(node) {
+				node.Weight = d.DoorWeight
+			}
+		}
+	}
+	// Waypoints and further ends may lie outside the rooms
+	return connectStops(grid)
+}
+
+// split partitions the leaf, adding the rooms of the leaves and the corridors joining them. The whole grid
+// is always split if it can be, so there are two rooms to place the start and end in.
+func (d *Dungeon) split(nodes [][]*models.Node, rng *rand.Rand, leaf region, force bool, rooms *[]region, corridors *[][]*models.Node) {
+	width, height := leaf.x1-leaf.x0+1, leaf.y1-leaf.y0+1
+	canSplitX, canSplitY := width >= 2*d.MinLeafSize, height >= 2*d.MinLeafSize
+	mustSplit := force || width > d.MaxLeafSize || height > d.MaxLeafSize
+	if !mustSplit || !canSplitX && !canSplitY {
+		*rooms = append(*rooms, d.placeRoom(rng, leaf))
+		return
+	}
+
+	// Cut across the longer side, so leaves stay roughly square
+	vertical := canSplitX && (!canSplitY || width > height || width == height && rng.Intn(2) == 0)
+	var first, second region
+	if vertical {
+		at := leaf.x0 + d.MinLeafSize + rng.Intn(width-2*d.MinLeafSize+1)
+		first, second = region{leaf.x0, leaf.y0, at - 1, leaf.y1}, region{at, leaf.y0, leaf.x1, leaf.y1}
+	} else {
+		at := leaf.y0 + d.MinLeafSize + rng.Intn(height-2*d.MinLeafSize+1)
+		first, second = region{leaf.x0, leaf.y0, leaf.x1, at - 1}, region{leaf.x0, at, leaf.x1, leaf.y1}
+	}
+	before := len(*rooms)
+	d.split(nodes, rng, first, false, rooms, corridors)
+	middle := len(*rooms)
+	d.split(nodes, rng, second, false, rooms, corridors)
+
+	from, to := (*rooms)[before+rng.Intn(middle-before)], (*rooms)[middle+rng.Intn(len(*rooms)-middle)]
+	*corridors = append(*corridors, d.corridor(nodes, rng, from, to))
+}
+
+// placeRoom picks a random room inside the leaf, leaving a wall on every side
+func (d *Dungeon) placeRoom(rng *rand.Rand, leaf region) region {
+	width, height := leaf.x1-leaf.x0-1, leaf.y1-leaf.y0-1
+	minSize := d.MinLeafSize - 2
+	roomWidth := minSize + rng.Intn(width-minSize+1)
+	roomHeight := minSize + rng.Intn(height-minSize+1)
+	x0 := leaf.x0 + 1 + rng.Intn(width-roomWidth+1)
+	y0 := leaf.y0 + 1 + rng.Intn(height-roomHeight+1)
+	return region{x0, y0, x0 + roomWidth - 1, y0 + roomHeight - 1}
+}
+
+// corridor returns the nodes of an L-shaped corridor between the centers of two rooms
+func (d *Dungeon) corridor(nodes [][]*models.Node, rng *rand.Rand, from, to region) []*models.Node {
+	x, y := from.center()
+	toX, toY := to.center()
+	corridor := []*models.Node{nodes[y][x]}
+	horizontalFirst := rng.Intn(2) == 0
+	for x != toX || y != toY {
+		if x != toX && (horizontalFirst || y == toY) {
+			x += sign(toX - x)
+		} else {
+			y += sign(toY - y)
+		}
+		corridor = append(corridor, nodes[y][x])
+	}
+	return corridor
+}
+
+// placeStops moves the start into the first room and the end into the room farthest from it
+func (d *Dungeon) placeStops(grid *models.Grid, rooms []region) error {
+	startX, startY := rooms[0].center()
+	farthest, farthestDistance := rooms[1], -1
+	for _, r := range rooms[1:] {
+		x, y := r.center()
+		if distance := (x-startX)*(x-startX) + (y-startY)*(y-startY); distance > farthestDistance {
+			farthest, farthestDistance = r, distance
+		}
+	}
+	start, err := freeNode(grid, rooms[0])
+	if err != nil {
+		return err
+	}
+	end, err := freeNode(grid, farthest)
+	if err != nil {
+		return err
+	}
+
+	// Open the grid first, SetStart and SetEnd refuse walls
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	for _, row := range nodes {
+		for _, node := range row {
+			node.IsWall = false
+		}
+	}
+	// Park the end out of the way, start and end may be about to swap places
+	for _, row := range nodes {
+		for _, node := range row {
+			if !isFixed(node) && node != start && node != end {
+				if err := grid.SetEnd(node.X, node.Y); err != nil {
+					return err
+				}
+				if err := grid.SetStart(start.X, start.Y); err != nil {
+					return err
+				}
+				return grid.SetEnd(end.X, end.Y)
+			}
+		}
+	}
+	return errors.New("no free node to move the end through")
+}
+
+// freeNode returns the node of the room closest to its center that the start or end can move to
+func freeNode(grid *models.Grid, r region) (*models.Node, error) {
+	primaryEnd, err := grid.GetEnd()
+	if err != nil {
+		return nil, err
+	}
+	centerX, centerY := r.center()
+	var best *models.Node
+	bestDistance := 0
+	for y := r.y0; y <= r.y1; y++ {
+		for x := r.x0; x <= r.x1; x++ {
+			node, err := grid.GetNode(x, y)
+			if err != nil {
+				return nil, err
+			}
+			if node.IsWaypoint || node.IsEnd && node != primaryEnd {
+				continue
+			}
+			if distance := (x-centerX)*(x-centerX) + (y-centerY)*(y-centerY); best == nil || distance < bestDistance {
+				best, bestDistance = node, distance
+			}
+		}
+	}
+	if best == nil {
+		return nil, errors.New("room has no free node")
+	}
+	return best, nil
+}
+
+func sign(v int) int {
+	if v < 0 {
+		return -1
+	}
+	if v > 0 {
+		return 1
+	}
+	return 0
+}
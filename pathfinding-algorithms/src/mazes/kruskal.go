@@ -0,0 +1,45 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// Kruskal builds a perfect maze with randomized Kruskal's algorithm. Walls are removed in random order
+// whenever they separate two cells that are not yet connected, tracked with union-find. Many small
+// passages form everywhere and merge, giving a maze without long corridors.
+type Kruskal struct{}
+
+func (k *Kruskal) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	var walls [][2]cell
+	for i := 0; i < l.size(); i++ {
+		c := l.cellAt(i)
+		l.open(c)
+		if c.x+1 < l.width {
+			walls = append(walls, [2]cell{c, {c.x + 1, c.y}})
+		}
+		if c.y+1 < l.height {
+			walls = append(walls, [2]cell{c, {c.x, c.y + 1}})
+		}
+	}
+	rng.Shuffle(len(walls), func(i, j int) {
+		walls[i], walls[j] = walls[j], walls[i]
+	})
+
+	sets := datastructures.NewUnionFind(l.size())
+	for _, wall := range walls {
+		if sets.Union(l.index(wall[0]), l.index(wall[1])) {
+			l.connect(wall[0], wall[1])
+		}
+	}
+	return l.finish()
+}
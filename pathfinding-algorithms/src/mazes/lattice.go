@@ -0,0 +1,101 @@
+package mazes
+
+import (
+	"pathfinding-algorithms/models"
+)
+
+// cell is a maze cell in lattice coordinates
+type cell struct {
+	x, y int
+}
+
+// lattice views the grid as maze cells at even coordinates, the nodes between two cells are the walls
+// that get carved to connect them. Odd sides end on a row or column of cells, even sides have one spare
+// row or column of wall along the bottom or right edge, so walls are never doubled.
+type lattice struct {
+	grid          *models.Grid
+	maze          [][]*models.Node
+	width, height int // Number of cells
+}
+
+// newLattice fills the grid with walls and returns its cells. Grids from models.NewGrid are at least 10x10,
+// so there are always several cells to connect.
+func newLattice(grid *models.Grid) (*lattice, error) {
+	width, height := grid.GetWidth(), grid.GetHeight()
+	if err := fillWalls(grid); err != nil {
+		return nil, err
+	}
+	maze, err := grid.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	return &lattice{grid: grid, maze: maze, width: (width + 1) / 2, height: (height + 1) / 2}, nil
+}
+
+func (l *lattice) size() int {
+	return l.width * l.height
+}
+
+// index numbers the cells row by row
+func (l *lattice) index(c cell) int {
+	return c.y*l.width + c.x
+}
+
+func (l *lattice) cellAt(index int) cell {
+	return cell{index % l.width, index / l.width}
+}
+
+// neighbors returns the cells next to c in lattice coordinates
+func (l *lattice) neighbors(c cell) []cell {
+	var neighbors []cell
+	for _, d := range orthogonal {
+		nx, ny := c.x+d.Dx, c.y+d.Dy
+		if nx >= 0 && nx < l.width && ny >= 0 && ny < l.height {
+			neighbors = append(neighbors, cell{nx, ny})
+		}
+	}
+	return neighbors
+}
+
+// open carves the node of c
+func (l *lattice) open(c cell) {
+	l.maze[2*c.y][2*c.x].IsWall = false
+}
+
+// connect carves both cells and the wall between them
+func (l *lattice) connect(a, b cell) {
+	l.open(a)
+	l.open(b)
+	l.maze[a.y+b.y][a.x+b.x].IsWall = false
+}
+
+// finish connects the start, ends and waypoints to the maze. Fixed nodes off the lattice that ended up
+// enclosed by walls get a way out to a neighbouring cell first, anything still cut off is tunnelled to.
+func (l *lattice) finish() error {
+	height, width := len(l.maze), len(l.maze[0])
+	for y, row := range l.maze {
+		for x, node := range row {
+			if !isFixed(node) {
+				continue
+			}
+			var enclosing *models.Node
+			enclosed := true
+			for _, d := range orthogonal {
+				nx, ny := x+d.Dx, y+d.Dy
+				if nx < 0 || nx >= width || ny < 0 || ny >= height {
+					continue
+				}
+				if !l.maze[ny][nx].IsWall {
+					enclosed = false
+					break
+				}
+				enclosing = l.maze[ny][nx]
+			}
+			// Only nodes at two odd coordinates can be enclosed, every wall around them touches a cell
+			if enclosed && enclosing != nil {
+				enclosing.IsWall = false
+			}
+		}
+	}
+	return connectStops(l.grid)
+}
@@ -0,0 +1,168 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+	"sort"
+)
+
+// MazeGenerator turns a grid into a maze. Start, end and waypoint nodes are never walled over.
+type MazeGenerator interface {
+	Generate(grid *models.Grid, rng *rand.Rand) error
+}
+
+// Recorder is notified after each construction step of an animated generator
+type Recorder interface {
+	Record() error
+}
+
+// AnimatedGenerator is implemented by generators that can report their construction steps
+type AnimatedGenerator interface {
+	MazeGenerator
+	SetRecorder(recorder Recorder)
+}
+
+type GeneratorID int
+
+const (
+	RecursiveBacktracker GeneratorID = iota
+	RandomizedPrim
+	RandomizedKruskal
+	UniformWilson
+	UniformAldousBroder
+	RecursiveDivision
+	CellularCave
+	BSPDungeon
+	NoiseTerrain
+)
+
+// Generator is a registered maze generator
+type Generator struct {
+	ID   GeneratorID
+	Name string
+	New  func() MazeGenerator
+}
+
+var generatorsMap = map[GeneratorID]Generator{
+	RecursiveBacktracker: {
+		ID:   RecursiveBacktracker,
+		Name: "Recursive Backtracker",
+		New: func() MazeGenerator {
+			return &Backtracker{}
+		},
+	},
+	RandomizedPrim: {
+		ID:   RandomizedPrim,
+		Name: "Randomized Prim's",
+		New: func() MazeGenerator {
+			return &Prim{}
+		},
+	},
+	RandomizedKruskal: {
+		ID:   RandomizedKruskal,
+		Name: "Randomized Kruskal's",
+		New: func() MazeGenerator {
+			return &Kruskal{}
+		},
+	},
+	UniformWilson: {
+		ID:   UniformWilson,
+		Name: "Wilson's",
+		New: func() MazeGenerator {
+			return &Wilson{}
+		},
+	},
+	UniformAldousBroder: {
+		ID:   UniformAldousBroder,
+		Name: "Aldous-Broder",
+		New: func() MazeGenerator {
+			return &AldousBroder{}
+		},
+	},
+	RecursiveDivision: {
+		ID:   RecursiveDivision,
+		Name: "Recursive Division",
+		New: func() MazeGenerator {
+			return &Division{}
+		},
+	},
+	CellularCave: {
+		ID:   CellularCave,
+		Name: "Cellular Automata Cave",
+		New: func() MazeGenerator {
+			return NewCave()
+		},
+	},
+	BSPDungeon: {
+		ID:   BSPDungeon,
+		Name: "BSP Dungeon",
+		New: func() MazeGenerator {
+			return NewDungeon()
+		},
+	},
+	NoiseTerrain: {
+		ID:   NoiseTerrain,
+		Name: "Noise Terrain",
+		New: func() MazeGenerator {
+			return NewTerrain()
+		},
+	},
+}
+
+// Register adds a generator under a new ID
+func Register(id GeneratorID, name string, factory func() MazeGenerator) error {
+	if factory == nil {
+		return errors.New("generator factory is nil")
+	}
+	if _, exists := generatorsMap[id]; exists {
+		return errors.New("generator already registered")
+	}
+	generatorsMap[id] = Generator{ID: id, Name: name, New: factory}
+	return nil
+}
+
+// New creates the generator registered under id
+func New(id GeneratorID) (MazeGenerator, error) {
+	generator, exists := generatorsMap[id]
+	if !exists {
+		return nil, errors.New("generator not found")
+	}
+	return generator.New(), nil
+}
+
+// List returns every registered generator ordered by ID
+func List() []Generator {
+	generators := make([]Generator, 0, len(generatorsMap))
+	for _, generator := range generatorsMap {
+		generators = append(generators, generator)
+	}
+	sort.Slice(generators, func(i, j int) bool {
+		return generators[i].ID < generators[j].ID
+	})
+	return generators
+}
+
+// orthogonal are the directions between neighbouring nodes
+var orthogonal = []models.Point{{Dx: 0, Dy: -1}, {Dx: -1, Dy: 0}, {Dx: 1, Dy: 0}, {Dx: 0, Dy: 1}}
+
+// isFixed reports whether node has to stay open, whatever the generator does
+func isFixed(node *models.Node) bool {
+	return node.IsStart || node.IsEnd || node.IsWaypoint
+}
+
+// fillWalls turns every cell into an unweighted wall except for the fixed ones, generators carving passages
+// start from it
+func fillWalls(grid *models.Grid) error {
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	for _, row := range nodes {
+		for _, node := range row {
+			node.IsWall = !isFixed(node)
+			node.Weight = 0
+		}
+	}
+	return nil
+}
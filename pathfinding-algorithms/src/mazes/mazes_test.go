@@ -0,0 +1,363 @@
+package mazes_test
+
+import (
+	"math/rand"
+	"pathfinding-algorithms/mazes"
+	"pathfinding-algorithms/models"
+	"testing"
+)
+
+// walls renders the walls of the grid so mazes can be compared
+func walls(t *testing.T, grid *models.Grid) string {
+	t.Helper()
+	nodes, _ := grid.GetNodes()
+	var out []byte
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.IsWall {
+				out = append(out, '#')
+			} else {
+				out = append(out, '.')
+			}
+		}
+		out = append(out, '\n')
+	}
+	return string(out)
+}
+
+func TestRegisteredGeneratorsAreReproducible(t *testing.T) {
+	generators := mazes.List()
+	if len(generators) == 0 {
+		t.Fatalf("No generators registered")
+	}
+	for _, info := range generators {
+		var rendered []string
+		for i := 0; i < 2; i++ {
+			grid, _ := models.NewGrid(31, 21)
+			generator, err := mazes.New(info.ID)
+			if err != nil {
+				t.Fatalf("New(%d) failed: %v", info.ID, err)
+			}
+			if err := generator.Generate(grid, rand.New(rand.NewSource(42))); err != nil {
+				t.Fatalf("%s: Generate failed: %v", info.Name, err)
+			}
+			start, _ := grid.GetStart()
+			end, _ := grid.GetEnd()
+			if start.IsWall || end.IsWall {
+				t.Fatalf("%s: start or end was walled over", info.Name)
+			}
+			rendered = append(rendered, walls(t, grid))
+		}
+		if rendered[0] != rendered[1] {
+			t.Fatalf("%s: the same seed produced different mazes", info.Name)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := mazes.Register(mazes.RecursiveBacktracker, "Duplicate", func() mazes.MazeGenerator {
+		return &mazes.Backtracker{}
+	}); err == nil {
+		t.Fatalf("Registering an existing ID succeeded")
+	}
+	if _, err := mazes.New(-1); err == nil {
+		t.Fatalf("Creating an unregistered generator succeeded")
+	}
+}
+
+// reachable counts the open nodes reachable from the start and reports whether the end is among them
+func reachable(t *testing.T, grid *models.Grid) (int, bool) {
+	t.Helper()
+	start, _ := grid.GetStart()
+	seen := map[*models.Node]bool{start: true}
+	queue := []*models.Node{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		neighbors, _ := grid.GetNeighbors(current)
+		for _, neighbor := range neighbors {
+			if !neighbor.IsWall && !seen[neighbor] {
+				seen[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	end, _ := grid.GetEnd()
+	return len(seen), seen[end]
+}
+
+func TestPerfectMazesConnectEverything(t *testing.T) {
+	for _, id := range []mazes.GeneratorID{mazes.RecursiveBacktracker, mazes.RandomizedPrim, mazes.RandomizedKruskal, mazes.UniformWilson, mazes.UniformAldousBroder, mazes.RecursiveDivision} {
+		for _, size := range [][2]int{{10, 10}, {31, 21}, {40, 25}} {
+			grid, _ := models.NewGrid(size[0], size[1])
+			generator, _ := mazes.New(id)
+			if err := generator.Generate(grid, rand.New(rand.NewSource(7))); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			open := 0
+			nodes, _ := grid.GetNodes()
+			for _, row := range nodes {
+				for _, node := range row {
+					if !node.IsWall {
+						open++
+					}
+				}
+			}
+			count, endReached := reachable(t, grid)
+			if !endReached || count != open {
+				t.Fatalf("Generator %d on %v: %d of %d open cells reachable, end reached %v", id, size, count, open, endReached)
+			}
+		}
+	}
+}
+
+// wallRecorder counts the walls of the grid at every recorded step
+type wallRecorder struct {
+	grid  *models.Grid
+	walls []int
+}
+
+func (r *wallRecorder) Record() error {
+	nodes, _ := r.grid.GetNodes()
+	count := 0
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.IsWall {
+				count++
+			}
+		}
+	}
+	r.walls = append(r.walls, count)
+	return nil
+}
+
+func TestLatticeMazesConnectStopsOnAnyParity(t *testing.T) {
+	lattice := []mazes.GeneratorID{mazes.RecursiveBacktracker, mazes.RandomizedPrim, mazes.RandomizedKruskal,
+		mazes.UniformWilson, mazes.UniformAldousBroder, mazes.RecursiveDivision}
+	for _, id := range lattice {
+		for _, size := range [][2]int{{12, 10}, {13, 11}, {12, 11}} {
+			grid, _ := models.NewGrid(size[0], size[1])
+			// Odd coordinates are walls between cells and the corner of an even grid is on the spare edge
+			if err := grid.SetStart(3, 5); err != nil {
+				t.Fatalf("SetStart failed: %v", err)
+			}
+			if err := grid.SetWall(size[0]-1, size[1]-1, false); err != nil {
+				t.Fatalf("SetWall failed: %v", err)
+			}
+			if err := grid.SetEnd(size[0]-1, size[1]-1); err != nil {
+				t.Fatalf("SetEnd failed: %v", err)
+			}
+			generator, _ := mazes.New(id)
+			if err := generator.Generate(grid, rand.New(rand.NewSource(5))); err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if _, endReached := reachable(t, grid); !endReached {
+				t.Fatalf("Generator %d on %v: end is not connected to the start", id, size)
+			}
+		}
+	}
+}
+
+func TestDivisionRecordsConstruction(t *testing.T) {
+	grid, _ := models.NewGrid(21, 15)
+	recorder := &wallRecorder{grid: grid}
+	division := &mazes.Division{}
+	division.SetRecorder(recorder)
+	if err := division.Generate(grid, rand.New(rand.NewSource(3))); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	// One step per wall of the 11x8 cell maze, which has 11*8-1 passages between its cells
+	if len(recorder.walls) < 11*8-1 {
+		t.Fatalf("Expected a recorded step per wall, got %d", len(recorder.walls))
+	}
+	if recorder.walls[0] != 0 {
+		t.Fatalf("Construction should start from an open grid, got %d walls", recorder.walls[0])
+	}
+	for i := 1; i < len(recorder.walls); i++ {
+		if recorder.walls[i] < recorder.walls[i-1] {
+			t.Fatalf("Walls were removed between steps %d and %d", i-1, i)
+		}
+	}
+}
+
+func TestCaveConnectsStartAndEnd(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		grid, _ := models.NewGrid(40, 30)
+		grid.AddWaypoint(3, 27)
+		cave := mazes.NewCave()
+		// Dense fills break the cave into many pockets that have to be tunnelled together
+		cave.FillProbability = 0.6
+		if err := cave.Generate(grid, rand.New(rand.NewSource(seed))); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if _, endReached := reachable(t, grid); !endReached {
+			t.Fatalf("Seed %d: end is not connected to the start", seed)
+		}
+		waypoint, _ := grid.GetNode(3, 27)
+		if waypoint.IsWall {
+			t.Fatalf("Seed %d: waypoint was walled over", seed)
+		}
+	}
+	if err := (&mazes.Cave{FillProbability: 2}).Generate(&models.Grid{}, rand.New(rand.NewSource(0))); err == nil {
+		t.Fatalf("Invalid fill probability was accepted")
+	}
+}
+
+func countDeadEnds(t *testing.T, grid *models.Grid) int {
+	t.Helper()
+	nodes, _ := grid.GetNodes()
+	count := 0
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.IsWall {
+				continue
+			}
+			open := 0
+			neighbors, _ := grid.GetNeighbors(node)
+			for _, neighbor := range neighbors {
+				if !neighbor.IsWall {
+					open++
+				}
+			}
+			if open == 1 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestBraidRemovesDeadEnds(t *testing.T) {
+	perfect := map[float64]int{}
+	braided := map[float64]int{}
+	for _, fraction := range []float64{0, 0.5, 1} {
+		grid, _ := models.NewGrid(41, 31)
+		rng := rand.New(rand.NewSource(5))
+		(&mazes.Kruskal{}).Generate(grid, rng)
+		perfect[fraction] = countDeadEnds(t, grid)
+		if err := mazes.Braid(grid, rng, fraction); err != nil {
+			t.Fatalf("Braid failed: %v", err)
+		}
+		braided[fraction] = countDeadEnds(t, grid)
+		if _, endReached := reachable(t, grid); !endReached {
+			t.Fatalf("Braiding disconnected the end")
+		}
+	}
+	if braided[0] != perfect[0] {
+		t.Fatalf("Braiding nothing changed the dead ends from %d to %d", perfect[0], braided[0])
+	}
+	if braided[0.5] > perfect[0.5]/2 || braided[0.5] == 0 {
+		t.Fatalf("Braiding half left %d of %d dead ends", braided[0.5], perfect[0.5])
+	}
+	if braided[1] != 0 {
+		t.Fatalf("Braiding everything left %d dead ends", braided[1])
+	}
+	if err := mazes.Braid(&models.Grid{}, rand.New(rand.NewSource(0)), 1.5); err == nil {
+		t.Fatalf("Invalid braid fraction was accepted")
+	}
+}
+
+func TestDungeonPlacesStopsInConnectedRooms(t *testing.T) {
+	for _, doorWeight := range []int{0, 5} {
+		grid, _ := models.NewGrid(50, 40)
+		grid.SetWall(1, 1, true)
+		dungeon := mazes.NewDungeon()
+		dungeon.DoorWeight = doorWeight
+		if err := dungeon.Generate(grid, rand.New(rand.NewSource(11))); err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if _, endReached := reachable(t, grid); !endReached {
+			t.Fatalf("End is not connected to the start")
+		}
+		start, _ := grid.GetStart()
+		end, _ := grid.GetEnd()
+		if dx, dy := start.X-end.X, start.Y-end.Y; dx*dx+dy*dy < 20*20 {
+			t.Fatalf("Start %v and end %v are not in rooms far apart", start, end)
+		}
+		doors := 0
+		nodes, _ := grid.GetNodes()
+		for _, row := range nodes {
+			for _, node := range row {
+				if node.Weight > 1 {
+					doors++
+				}
+			}
+		}
+		if doorWeight == 0 && doors != 0 || doorWeight > 1 && doors == 0 {
+			t.Fatalf("Found %d doors with door weight %d", doors, doorWeight)
+		}
+	}
+}
+
+func TestTerrainWeightsFollowElevation(t *testing.T) {
+	grid, _ := models.NewGrid(60, 40)
+	terrain := mazes.NewTerrain()
+	if err := terrain.Generate(grid, rand.New(rand.NewSource(9))); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if _, endReached := reachable(t, grid); !endReached {
+		t.Fatalf("End is not connected to the start")
+	}
+	weights := map[int]bool{}
+	walls := 0
+	nodes, _ := grid.GetNodes()
+	for _, row := range nodes {
+		for _, node := range row {
+			if node.IsWall {
+				walls++
+				continue
+			}
+			if node.Weight < 1 || node.Weight > terrain.MaxWeight {
+				t.Fatalf("Weight %d of %v is outside 1..%d", node.Weight, node, terrain.MaxWeight)
+			}
+			weights[node.Weight] = true
+		}
+	}
+	if walls == 0 || len(weights) < terrain.MaxWeight/2 {
+		t.Fatalf("Expected water, mountains and varied land, got %d walls and weights %v", walls, weights)
+	}
+
+	terrain.Octaves = 0
+	if err := terrain.Generate(grid, rand.New(rand.NewSource(9))); err == nil {
+		t.Fatalf("Terrain without octaves was accepted")
+	}
+}
+
+func TestAnalyzeMeasuresCorridorWithSpur(t *testing.T) {
+	// A corridor from the start at (2,5) to the end at (8,5) with a spur going up from (5,5)
+	grid, _ := models.NewGrid(10, 10)
+	nodes, _ := grid.GetNodes()
+	for _, row := range nodes {
+		for _, node := range row {
+			onCorridor := node.Y == 5 && node.X >= 2 && node.X <= 8
+			onSpur := node.X == 5 && node.Y >= 2 && node.Y < 5
+			node.IsWall = !onCorridor && !onSpur
+		}
+	}
+	metrics, err := mazes.Analyze(grid)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	want := mazes.Metrics{
+		Cells:          10,
+		DeadEnds:       1,
+		Branching:      [5]int{0, 3, 6, 1, 0},
+		SolutionLength: 7,
+		DecisionPoints: 1,
+		RiverFactor:    3,
+		OffSolution:    30,
+	}
+	got := *metrics
+	got.Difficulty = 0
+	if got != want {
+		t.Fatalf("Expected %+v, got %+v", want, got)
+	}
+	if metrics.Difficulty <= 0 || metrics.Difficulty > 100 {
+		t.Fatalf("Difficulty %v is outside (0, 100]", metrics.Difficulty)
+	}
+
+	nodes[5][6].IsWall = true
+	if _, err := mazes.Analyze(grid); err == nil {
+		t.Fatalf("Analyze accepted a grid with the end cut off")
+	}
+}
@@ -0,0 +1,106 @@
+package mazes
+
+import (
+	"errors"
+	"pathfinding-algorithms/models"
+)
+
+// Metrics describes the structure of the part of a maze that can be reached from the start. The solution is the
+// shortest walk from the start to the closest end.
+type Metrics struct {
+	Cells          int    // Open nodes reachable from the start
+	DeadEnds       int    // Reachable nodes with a single open neighbour, the start, ends and waypoints excluded
+	Branching      [5]int // Reachable nodes by their number of open neighbours
+	SolutionLength int    // Nodes on the solution, start and end included
+	DecisionPoints int    // Nodes on the solution that offer a way off it
+	// RiverFactor is the average number of nodes off the solution per dead end. Low values are many short
+	// dead ends that are quickly ruled out, high values are few long ones that lead far astray.
+	RiverFactor float64
+	OffSolution float64 // Percentage of reachable nodes not on the solution
+	Difficulty  float64 // Score from 0 to 100, see difficulty
+}
+
+// Analyze measures the maze on the grid. It fails when no end can be reached from the start.
+func Analyze(grid *models.Grid) (*Metrics, error) {
+	if grid == nil {
+		return nil, errors.New("grid is nil")
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	start, err := grid.GetStart()
+	if err != nil {
+		return nil, err
+	}
+
+	// Breadth-first search from the start, the first end dequeued is the closest
+	parents := map[*models.Node]*models.Node{start: nil}
+	queue := []*models.Node{start}
+	var end *models.Node
+	metrics := &Metrics{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if end == nil && current.IsEnd {
+			end = current
+		}
+		neighbors := openNeighbors(nodes, current)
+		metrics.Cells++
+		metrics.Branching[len(neighbors)]++
+		if len(neighbors) == 1 && !isFixed(current) {
+			metrics.DeadEnds++
+		}
+		for _, neighbor := range neighbors {
+			if _, seen := parents[neighbor]; !seen {
+				parents[neighbor] = current
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	if end == nil {
+		return nil, errors.New("no end is reachable from the start")
+	}
+
+	for node := end; node != nil; node = parents[node] {
+		metrics.SolutionLength++
+		// Two neighbours on a solution node are the way in and the way on, the start and end have one of those
+		onward := 2
+		if node == start || node == end {
+			onward = 1
+		}
+		if node != end && len(openNeighbors(nodes, node)) > onward {
+			metrics.DecisionPoints++
+		}
+	}
+	off := metrics.Cells - metrics.SolutionLength
+	metrics.OffSolution = 100 * float64(off) / float64(metrics.Cells)
+	if metrics.DeadEnds > 0 {
+		metrics.RiverFactor = float64(off) / float64(metrics.DeadEnds)
+	}
+	metrics.Difficulty = difficulty(metrics)
+	return metrics, nil
+}
+
+// difficulty scores how hard the maze is to solve by hand. Every decision point is a chance to go wrong, what
+// going wrong costs grows with the share of the maze off the solution and with how long the dead ends run
+// before they give themselves away. Open areas are easy however they are laid out, the ends can be seen
+// across them, so the score is scaled by the share of nodes in corridors. Each factor saturates, which keeps
+// the score between 0 and 100.
+func difficulty(m *Metrics) float64 {
+	decisions := float64(m.DecisionPoints) / float64(m.DecisionPoints+5)
+	river := m.RiverFactor / (m.RiverFactor + 2)
+	corridors := float64(m.Branching[1]+m.Branching[2]) / float64(m.Cells)
+	return m.OffSolution * decisions * river * corridors
+}
+
+// openNeighbors returns the open nodes orthogonally next to node
+func openNeighbors(nodes [][]*models.Node, node *models.Node) []*models.Node {
+	var neighbors []*models.Node
+	for _, d := range orthogonal {
+		if neighbor := nodeAt(nodes, node.X+d.Dx, node.Y+d.Dy); neighbor != nil && !neighbor.IsWall {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	return neighbors
+}
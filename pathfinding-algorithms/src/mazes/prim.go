@@ -0,0 +1,53 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Prim grows a perfect maze with randomized Prim's algorithm. The maze spreads out from a single cell in
+// every direction at once, which leaves many short dead ends.
+type Prim struct{}
+
+func (p *Prim) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	inMaze := make([]bool, l.size())
+	inFrontier := make([]bool, l.size())
+	var frontier []cell
+	add := func(c cell) {
+		inMaze[l.index(c)] = true
+		l.open(c)
+		for _, neighbor := range l.neighbors(c) {
+			if !inMaze[l.index(neighbor)] && !inFrontier[l.index(neighbor)] {
+				inFrontier[l.index(neighbor)] = true
+				frontier = append(frontier, neighbor)
+			}
+		}
+	}
+	add(l.cellAt(rng.Intn(l.size())))
+
+	for len(frontier) > 0 {
+		// Take a random frontier cell and join it to a random neighbour already in the maze
+		i := rng.Intn(len(frontier))
+		current := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		var joined []cell
+		for _, neighbor := range l.neighbors(current) {
+			if inMaze[l.index(neighbor)] {
+				joined = append(joined, neighbor)
+			}
+		}
+		l.connect(current, joined[rng.Intn(len(joined))])
+		add(current)
+	}
+	return l.finish()
+}
@@ -0,0 +1,132 @@
+package mazes
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Terrain generates natural looking weighted maps from Perlin noise. Several octaves of noise are summed into
+// an elevation scaled to 0..1. Low ground is water and high ground is mountains, both impassable, and the
+// land in between gets more costly to cross the higher it lies.
+type Terrain struct {
+	Scale         float64 // Size in nodes of the largest features
+	Octaves       int     // Number of noise layers, each adding finer detail at twice the frequency
+	Persistence   float64 // Share of the amplitude kept by each further octave
+	WaterLevel    float64 // Elevation below which nodes are water
+	MountainLevel float64 // Elevation above which nodes are mountains
+	MaxWeight     int     // Weight of the highest land, the lowest weighs 1
+}
+
+// NewTerrain returns a terrain generator with lakes, mountain ranges and rolling land in between
+func NewTerrain() *Terrain {
+	return &Terrain{Scale: 24, Octaves: 4, Persistence: 0.5, WaterLevel: 0.3, MountainLevel: 0.8, MaxWeight: 9}
+}
+
+func (t *Terrain) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	if t.Scale <= 0 || t.Octaves < 1 {
+		return errors.New("scale must be positive and there must be at least one octave")
+	}
+	if t.WaterLevel > t.MountainLevel {
+		return errors.New("water level must not be above the mountain level")
+	}
+	if t.MaxWeight < 1 {
+		return errors.New("max weight must be at least 1")
+	}
+	nodes, err := grid.GetNodes()
+	if err != nil {
+		return err
+	}
+	noise := newPerlin(rng)
+	elevations := make([][]float64, len(nodes))
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for y, row := range nodes {
+		elevations[y] = make([]float64, len(row))
+		for x := range row {
+			elevation := noise.fractal(float64(x)/t.Scale, float64(y)/t.Scale, t.Octaves, t.Persistence)
+			elevations[y][x] = elevation
+			lowest, highest = math.Min(lowest, elevation), math.Max(highest, elevation)
+		}
+	}
+
+	for y, row := range nodes {
+		for x, node := range row {
+			elevation := 0.0
+			if highest > lowest {
+				elevation = (elevations[y][x] - lowest) / (highest - lowest)
+			}
+			node.IsWall = !isFixed(node) && (elevation < t.WaterLevel || elevation > t.MountainLevel)
+			node.Weight = 1
+			if t.MountainLevel > t.WaterLevel && elevation > t.WaterLevel {
+				land := math.Min((elevation-t.WaterLevel)/(t.MountainLevel-t.WaterLevel), 1)
+				node.Weight = 1 + int(math.Round(land*float64(t.MaxWeight-1)))
+			}
+		}
+	}
+	return connectStops(grid)
+}
+
+// perlin is two dimensional gradient noise over a permutation table shuffled with the seed
+type perlin struct {
+	permutation [512]int
+}
+
+func newPerlin(rng *rand.Rand) *perlin {
+	p := &perlin{}
+	for i, v := range rng.Perm(256) {
+		p.permutation[i], p.permutation[i+256] = v, v
+	}
+	return p
+}
+
+// fractal sums octaves of noise at doubling frequencies and falling amplitudes, the result lies in -1..1
+func (p *perlin) fractal(x, y float64, octaves int, persistence float64) float64 {
+	total, amplitude, totalAmplitude, frequency := 0.0, 1.0, 0.0, 1.0
+	for i := 0; i < octaves; i++ {
+		total += amplitude * p.noise(x*frequency, y*frequency)
+		totalAmplitude += amplitude
+		amplitude *= persistence
+		frequency *= 2
+	}
+	return total / totalAmplitude
+}
+
+func (p *perlin) noise(x, y float64) float64 {
+	xi, yi := int(math.Floor(x))&255, int(math.Floor(y))&255
+	xf, yf := x-math.Floor(x), y-math.Floor(y)
+	u, v := fade(xf), fade(yf)
+	aa := p.permutation[p.permutation[xi]+yi]
+	ab := p.permutation[p.permutation[xi]+yi+1]
+	ba := p.permutation[p.permutation[xi+1]+yi]
+	bb := p.permutation[p.permutation[xi+1]+yi+1]
+	top := lerp(gradient(aa, xf, yf), gradient(ba, xf-1, yf), u)
+	bottom := lerp(gradient(ab, xf, yf-1), gradient(bb, xf-1, yf-1), u)
+	return lerp(top, bottom, v)
+}
+
+// fade eases the interpolation so the noise is smooth across lattice borders
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
+
+// gradient picks one of four diagonal gradients by hash and returns its dot product with (x, y)
+func gradient(hash int, x, y float64) float64 {
+	switch hash & 3 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	default:
+		return -x - y
+	}
+}
@@ -0,0 +1,46 @@
+package mazes
+
+import (
+	"errors"
+	"math/rand"
+	"pathfinding-algorithms/models"
+)
+
+// Wilson builds a uniform spanning tree with Wilson's algorithm. From every cell outside the maze a random
+// walk runs until it hits the maze, and the walk with its loops erased is added. Every perfect maze on the
+// grid is equally likely.
+type Wilson struct{}
+
+func (w *Wilson) Generate(grid *models.Grid, rng *rand.Rand) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	l, err := newLattice(grid)
+	if err != nil {
+		return err
+	}
+	inMaze := make([]bool, l.size())
+	first := rng.Intn(l.size())
+	inMaze[first] = true
+	l.open(l.cellAt(first))
+
+	// Remembering only the last exit of every cell erases the loops of the walk
+	exits := make([]cell, l.size())
+	for _, i := range rng.Perm(l.size()) {
+		if inMaze[i] {
+			continue
+		}
+		current := l.cellAt(i)
+		for !inMaze[l.index(current)] {
+			neighbors := l.neighbors(current)
+			next := neighbors[rng.Intn(len(neighbors))]
+			exits[l.index(current)] = next
+			current = next
+		}
+		for current = l.cellAt(i); !inMaze[l.index(current)]; current = exits[l.index(current)] {
+			inMaze[l.index(current)] = true
+			l.connect(current, exits[l.index(current)])
+		}
+	}
+	return l.finish()
+}
@@ -5,20 +5,39 @@ import (
 )
 
 type Node struct {
-	X, Y    int
-	Visited bool
-	IsWall  bool
-	IsStart bool
-	IsEnd   bool
+	X, Y       int
+	Visited    bool
+	IsWall     bool
+	IsStart    bool
+	IsEnd      bool
+	IsWaypoint bool
+	Weight     int // Weight of the node, 0 and 1 both mean a normal node, see Cost
+}
+
+// Cost returns the weight of the node, normal nodes weigh 1. A step between two nodes costs the average of
+// their weights, so entering a node of weight 9 from a normal one costs 5, and leaving it again costs 5 more.
+func (n *Node) Cost() float64 {
+	if n.Weight < 1 {
+		return 1
+	}
+	return float64(n.Weight)
 }
 
 type Grid struct {
 	width, height    int // Dimensions of the grid
 	nodes            [][]*Node
-	start, end       *Node
+	start            *Node
+	ends             []*Node // Routes finish at whichever end is closest, the first one is the primary end
+	waypoints        []*Node // Visited in order between start and end
 	diagonalMovement bool
 }
 
+// Snapshot is a copy of the grid's nodes taken during a search, Leg is the index of the route leg being searched
+type Snapshot struct {
+	Leg   int
+	Nodes [][]*Node
+}
+
 type Point struct{ Dx, Dy int }
 
 var diagonalDirections = []Point{
@@ -62,7 +81,7 @@ func NewGrid(width, height int) (*Grid, error) {
 		height:           height,
 		nodes:            nodes,
 		start:            nodes[yMid][xMid1],
-		end:              nodes[yMid][xMid2+1],
+		ends:             []*Node{nodes[yMid][xMid2+1]},
 		diagonalMovement: false,
 	}, nil
 }
@@ -72,7 +91,7 @@ func (g *Grid) SetStart(x, y int) error {
 	if g == nil {
 		return errors.New("grid is nil")
 	}
-	if g.nodes[y][x].IsEnd || g.nodes[y][x].IsWall {
+	if g.nodes[y][x].IsEnd || g.nodes[y][x].IsWall || g.nodes[y][x].IsWaypoint {
 		return errors.New("invalid location")
 	}
 	g.nodes[g.start.Y][g.start.X].IsStart = false
@@ -84,23 +103,58 @@ func (g *Grid) SetStart(x, y int) error {
 	return errors.New("invalid location")
 }
 
-// SetEnd sets the end node
+// SetEnd moves the primary end node
 func (g *Grid) SetEnd(x, y int) error {
 	if g == nil {
 		return errors.New("grid is nil")
 	}
-	if g.nodes[y][x].IsStart || g.nodes[y][x].IsWall {
+	if g.nodes[y][x].IsStart || g.nodes[y][x].IsWall || g.nodes[y][x].IsWaypoint {
 		return errors.New("invalid location")
 	}
-	g.nodes[g.end.Y][g.end.X].IsEnd = false
+	if g.nodes[y][x].IsEnd && g.nodes[y][x] != g.ends[0] {
+		return errors.New("invalid location")
+	}
+	g.ends[0].IsEnd = false
 	if y >= 0 && y < g.height && x >= 0 && x < g.width {
 		g.nodes[y][x].IsEnd = true
-		g.end = g.nodes[y][x]
+		g.ends[0] = g.nodes[y][x]
 		return nil
 	}
 	return errors.New("invalid location")
 }
 
+// AddEnd adds another end node, routes finish at whichever end they reach first
+func (g *Grid) AddEnd(x, y int) error {
+	node, err := g.GetNode(x, y)
+	if err != nil {
+		return err
+	}
+	if node.IsStart || node.IsEnd || node.IsWall || node.IsWaypoint {
+		return errors.New("invalid location")
+	}
+	node.IsEnd = true
+	g.ends = append(g.ends, node)
+	return nil
+}
+
+// RemoveEnd removes the end node at the given location, the last remaining end can not be removed
+func (g *Grid) RemoveEnd(x, y int) error {
+	if g == nil {
+		return errors.New("grid is nil")
+	}
+	for i, end := range g.ends {
+		if end.X == x && end.Y == y {
+			if len(g.ends) == 1 {
+				return errors.New("grid needs at least one end")
+			}
+			end.IsEnd = false
+			g.ends = append(g.ends[:i], g.ends[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("end not found")
+}
+
 func (g *Grid) GetWidth() int {
 	if g == nil {
 		return 0
@@ -115,6 +169,18 @@ func (g *Grid) GetHeight() int {
 	return g.height
 }
 
+// largeGridNodes is the grid size from which a snapshot is only taken every few steps
+const largeGridNodes = 10000
+
+// SnapshotInterval returns how many search or construction steps to take per snapshot of the grid. Both the
+// number of steps and the size of each snapshot grow with the grid, so recording every step costs memory
+// quadratic in its size. The interval grows quadratically too, keeping large grids near the cost of a
+// 100x100 one instead of exhausting memory.
+func (g *Grid) SnapshotInterval() int {
+	scale := g.GetWidth() * g.GetHeight() / largeGridNodes
+	return 1 + scale*scale
+}
+
 func (g *Grid) GetNode(x, y int) (*Node, error) {
 	if g == nil {
 		return nil, errors.New("grid is nil")
@@ -132,18 +198,26 @@ func (g *Grid) GetStart() (*Node, error) {
 	return g.start, nil
 }
 
+// GetEnd returns the primary end node
 func (g *Grid) GetEnd() (*Node, error) {
 	if g == nil {
 		return nil, errors.New("grid is nil")
 	}
-	return g.end, nil
+	return g.ends[0], nil
+}
+
+func (g *Grid) GetEnds() ([]*Node, error) {
+	if g == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return g.ends, nil
 }
 
 func (g *Grid) SetWall(x, y int, isWall bool) error {
 	if g == nil {
 		return errors.New("grid is nil")
 	}
-	if g.nodes[y][x].IsStart || g.nodes[y][x].IsEnd {
+	if g.nodes[y][x].IsStart || g.nodes[y][x].IsEnd || g.nodes[y][x].IsWaypoint {
 		return errors.New("invalid location")
 	}
 	if y >= 0 && y < g.height && x >= 0 && x < g.width {
@@ -153,6 +227,80 @@ func (g *Grid) SetWall(x, y int, isWall bool) error {
 	return errors.New("invalid location")
 }
 
+// SetWeight sets the weight of the node at the given location, 0 or 1 for a normal node. Steps into and out of
+// the node each pay half of it, see Node.Cost.
+func (g *Grid) SetWeight(x, y, weight int) error {
+	node, err := g.GetNode(x, y)
+	if err != nil {
+		return err
+	}
+	if weight < 0 {
+		return errors.New("weight must not be negative")
+	}
+	node.Weight = weight
+	return nil
+}
+
+// AddWaypoint appends a waypoint that routes have to pass through after the previous ones
+func (g *Grid) AddWaypoint(x, y int) error {
+	node, err := g.GetNode(x, y)
+	if err != nil {
+		return err
+	}
+	if node.IsStart || node.IsEnd || node.IsWall || node.IsWaypoint {
+		return errors.New("invalid location")
+	}
+	node.IsWaypoint = true
+	g.waypoints = append(g.waypoints, node)
+	return nil
+}
+
+// RemoveWaypoint removes the waypoint at the given location, keeping the order of the others
+func (g *Grid) RemoveWaypoint(x, y int) error {
+	if g == nil {
+		return errors.New("grid is nil")
+	}
+	for i, waypoint := range g.waypoints {
+		if waypoint.X == x && waypoint.Y == y {
+			waypoint.IsWaypoint = false
+			g.waypoints = append(g.waypoints[:i], g.waypoints[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("waypoint not found")
+}
+
+func (g *Grid) GetWaypoints() ([]*Node, error) {
+	if g == nil {
+		return nil, errors.New("grid is nil")
+	}
+	return g.waypoints, nil
+}
+
+// GetStops returns the start followed by every waypoint in order, consecutive stops form the legs of a
+// route and its last leg continues to the closest end
+func (g *Grid) GetStops() ([]*Node, error) {
+	if g == nil {
+		return nil, errors.New("grid is nil")
+	}
+	stops := make([]*Node, 0, len(g.waypoints)+1)
+	stops = append(stops, g.start)
+	return append(stops, g.waypoints...), nil
+}
+
+// ClearVisited resets the visited flag of every node
+func (g *Grid) ClearVisited() error {
+	if g == nil {
+		return errors.New("grid is nil")
+	}
+	for _, row := range g.nodes {
+		for _, node := range row {
+			node.Visited = false
+		}
+	}
+	return nil
+}
+
 func (g *Grid) GetNeighbors(node *Node) ([]*Node, error) {
 	var neighbors []*Node
 	directions, err := g.GetDirections()
@@ -195,14 +343,25 @@ func (g *Grid) DeepCopy() (*Grid, error) {
 	for y, row := range g.nodes {
 		for x, node := range row {
 			newGrid.nodes[y][x] = &Node{
-				X:       node.X,
-				Y:       node.Y,
-				IsWall:  node.IsWall,
-				Visited: node.Visited,
-				IsStart: node.IsStart,
-				IsEnd:   node.IsEnd,
+				X:          node.X,
+				Y:          node.Y,
+				IsWall:     node.IsWall,
+				Visited:    node.Visited,
+				IsStart:    node.IsStart,
+				IsEnd:      node.IsEnd,
+				IsWaypoint: node.IsWaypoint,
+				Weight:     node.Weight,
 			}
 		}
 	}
+	newGrid.start = newGrid.nodes[g.start.Y][g.start.X]
+	newGrid.ends = make([]*Node, len(g.ends))
+	for i, end := range g.ends {
+		newGrid.ends[i] = newGrid.nodes[end.Y][end.X]
+	}
+	newGrid.waypoints = make([]*Node, len(g.waypoints))
+	for i, waypoint := range g.waypoints {
+		newGrid.waypoints[i] = newGrid.nodes[waypoint.Y][waypoint.X]
+	}
 	return newGrid, nil
 }
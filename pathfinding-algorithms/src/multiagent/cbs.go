@@ -0,0 +1,190 @@
+package multiagent
+
+import (
+	"errors"
+	"fmt"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// Upper bound on expanded constraint tree nodes, CBS is exponential in the number of conflicts
+const maxTreeNodes = 5000
+
+// CBS implements Conflict-Based Search. Agents are planned independently, and whenever two paths collide
+// the constraint tree branches into one child forbidding the collision for each of the two agents. The
+// cheapest collision free node is an optimal solution for the sum of arrival times.
+type CBS struct{}
+
+type treeNode struct {
+	id          int
+	parent      int
+	constraints []Constraint
+	paths       map[int][]Step
+	cost        int
+}
+
+// constraintSet holds the constraints of a single agent
+type constraintSet struct {
+	vertices map[state]bool
+	edges    map[[2]state]bool
+	last     map[Cell]int
+}
+
+func newConstraintSet(agent int, constraints []Constraint) *constraintSet {
+	set := &constraintSet{
+		vertices: make(map[state]bool),
+		edges:    make(map[[2]state]bool),
+		last:     make(map[Cell]int),
+	}
+	for _, c := range constraints {
+		if c.Agent != agent {
+			continue
+		}
+		if c.Edge {
+			set.edges[[2]state{{c.From, c.T - 1}, {c.To, c.T}}] = true
+		} else {
+			set.vertices[state{c.To, c.T}] = true
+			if last, exists := set.last[c.To]; !exists || c.T > last {
+				set.last[c.To] = c.T
+			}
+		}
+	}
+	return set
+}
+
+func (s *constraintSet) isBlocked(from, to Cell, t int) bool {
+	return s.vertices[state{to, t}] || s.edges[[2]state{{from, t - 1}, {to, t}}]
+}
+
+func (s *constraintSet) lastBlocked(cell Cell) int {
+	if last, exists := s.last[cell]; exists {
+		return last
+	}
+	return -1
+}
+
+// Solve finds an optimal set of collision free paths
+func (c *CBS) Solve(grid *models.Grid, agents []Agent) (*Solution, error) {
+	if err := validateAgents(grid, agents); err != nil {
+		return nil, err
+	}
+	goalTrees := make(map[int]*algorithms.ShortestPathTree)
+	for _, agent := range agents {
+		goal, err := grid.GetNode(agent.Goal.X, agent.Goal.Y)
+		if err != nil {
+			return nil, err
+		}
+		if goalTrees[agent.ID], err = algorithms.NewUnweightedShortestPathTree(grid, goal); err != nil {
+			return nil, err
+		}
+	}
+
+	horizon, err := planningHorizon(grid, goalTrees, agents)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &treeNode{parent: -1, paths: make(map[int][]Step)}
+	for _, agent := range agents {
+		path, err := c.planAgent(grid, goalTrees[agent.ID], agent, nil, horizon)
+		if err != nil {
+			return nil, fmt.Errorf("agent %d: %w", agent.ID, err)
+		}
+		root.paths[agent.ID] = path
+	}
+	root.cost = pathCost(root.paths)
+
+	solution := &Solution{}
+	// Equally cheap tree nodes are expanded in the order they were created
+	openSet := &datastructures.PriorityQueue[*treeNode]{}
+	if err := openSet.SetTieBreak(datastructures.FirstIn, 0); err != nil {
+		return nil, err
+	}
+	openSet.Init()
+	openSet.Insert(datastructures.NewItem(root, float64(root.cost)))
+	nextID := 1
+	for openSet.Len() > 0 && len(solution.Snapshots) < maxTreeNodes {
+		current := openSet.PopMin().GetValue()
+		conflicts := findConflicts(current.paths, true)
+		snapshot := Snapshot{
+			Node:        current.id,
+			Parent:      current.parent,
+			Cost:        current.cost,
+			Constraints: current.constraints,
+			Paths:       current.paths,
+		}
+		if len(conflicts) == 0 {
+			solution.Snapshots = append(solution.Snapshots, snapshot)
+			solution.Paths = current.paths
+			solution.Cost = current.cost
+			return solution, nil
+		}
+		conflict := conflicts[0]
+		snapshot.Conflict = &conflict
+		solution.Snapshots = append(solution.Snapshots, snapshot)
+
+		for _, constraint := range splitConflict(conflict) {
+			child := &treeNode{
+				id:          nextID,
+				parent:      current.id,
+				constraints: append(current.constraints[:len(current.constraints):len(current.constraints)], constraint),
+				paths:       make(map[int][]Step, len(current.paths)),
+			}
+			nextID++
+			for id, path := range current.paths {
+				child.paths[id] = path
+			}
+			agent := agentByID(agents, constraint.Agent)
+			path, err := c.planAgent(grid, goalTrees[agent.ID], agent, child.constraints, horizon)
+			if err != nil {
+				// This branch is infeasible, the other child may still succeed
+				continue
+			}
+			child.paths[agent.ID] = path
+			child.cost = pathCost(child.paths)
+			openSet.Insert(datastructures.NewItem(child, float64(child.cost)))
+		}
+	}
+	if openSet.Len() == 0 {
+		return nil, errors.New("no collision free solution exists")
+	}
+	return nil, errors.New("no solution found within the constraint tree limit")
+}
+
+// planAgent plans a single agent around its constraints, within horizon timesteps after the last of them
+func (c *CBS) planAgent(grid *models.Grid, goalTree *algorithms.ShortestPathTree, agent Agent, constraints []Constraint, horizon int) ([]Step, error) {
+	set := newConstraintSet(agent.ID, constraints)
+	latest := 0
+	for _, t := range set.last {
+		latest = max(latest, t)
+	}
+	for s := range set.edges {
+		latest = max(latest, s[1].t)
+	}
+	maxT := latest + horizon
+	return spaceTimeSearch(grid, goalTree, agent.Start, 0, agent.Goal, set, maxT, 0)
+}
+
+// splitConflict returns the two constraints, one per agent, that each resolve the conflict
+func splitConflict(conflict Conflict) []Constraint {
+	if conflict.Edge {
+		return []Constraint{
+			{Agent: conflict.AgentA, From: conflict.From, To: conflict.To, T: conflict.T, Edge: true},
+			{Agent: conflict.AgentB, From: conflict.To, To: conflict.From, T: conflict.T, Edge: true},
+		}
+	}
+	return []Constraint{
+		{Agent: conflict.AgentA, To: conflict.To, T: conflict.T},
+		{Agent: conflict.AgentB, To: conflict.To, T: conflict.T},
+	}
+}
+
+func agentByID(agents []Agent, id int) Agent {
+	for _, agent := range agents {
+		if agent.ID == id {
+			return agent
+		}
+	}
+	return Agent{}
+}
@@ -0,0 +1,213 @@
+package multiagent
+
+import (
+	"fmt"
+	"math"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/models"
+)
+
+// Cooperative implements prioritized planning. Agents are planned one at a time in the order they are
+// given, each avoiding the cells and moves already reserved by the agents before it. Without a window this
+// is Cooperative A*. With a window it is Windowed Hierarchical Cooperative A*: every agent only plans and
+// reserves the next Window steps, half of which are executed before everyone replans. The true distance to
+// the goal, ignoring other agents, serves as the hierarchical heuristic.
+//
+// Prioritized planning is fast but neither optimal nor complete. When an agent finds no path around the
+// reservations it falls back to ignoring them, and the resulting collisions are reported in the solution.
+type Cooperative struct {
+	Window int
+}
+
+// reservationTable records the space-time cells and moves claimed by agents
+type reservationTable struct {
+	cells   map[state]int
+	edges   map[[2]state]int
+	last    map[Cell]int
+	resting map[Cell]int // Agents staying at their goal forever, by the time they arrive
+}
+
+func newReservationTable() *reservationTable {
+	return &reservationTable{
+		cells:   make(map[state]int),
+		edges:   make(map[[2]state]int),
+		last:    make(map[Cell]int),
+		resting: make(map[Cell]int),
+	}
+}
+
+// reserve claims every step of path for agent, if rest is true the agent keeps its last cell afterwards
+func (r *reservationTable) reserve(agent int, path []Step, rest bool) {
+	for i, step := range path {
+		cell := Cell{step.X, step.Y}
+		r.cells[state{cell, step.T}] = agent
+		if last, exists := r.last[cell]; !exists || step.T > last {
+			r.last[cell] = step.T
+		}
+		if i > 0 {
+			from := Cell{path[i-1].X, path[i-1].Y}
+			r.edges[[2]state{{from, step.T - 1}, {cell, step.T}}] = agent
+		}
+	}
+	if rest {
+		final := path[len(path)-1]
+		r.resting[Cell{final.X, final.Y}] = final.T
+	}
+}
+
+// reservationView is the reservation table as seen by one agent
+type reservationView struct {
+	table *reservationTable
+	agent int
+}
+
+func (v reservationView) isBlocked(from, to Cell, t int) bool {
+	if agent, exists := v.table.cells[state{to, t}]; exists && agent != v.agent {
+		return true
+	}
+	// Swapping cells with another agent is an edge collision
+	if agent, exists := v.table.edges[[2]state{{to, t - 1}, {from, t}}]; exists && agent != v.agent {
+		return true
+	}
+	arrival, exists := v.table.resting[to]
+	return exists && t >= arrival
+}
+
+func (v reservationView) lastBlocked(cell Cell) int {
+	if _, exists := v.table.resting[cell]; exists {
+		return math.MaxInt
+	}
+	if last, exists := v.table.last[cell]; exists {
+		return last
+	}
+	return -1
+}
+
+// noReservations lets the fallback search ignore every other agent
+type noReservations struct{}
+
+func (noReservations) isBlocked(Cell, Cell, int) bool { return false }
+
+func (noReservations) lastBlocked(Cell) int { return -1 }
+
+// Solve plans the agents in priority order
+func (c *Cooperative) Solve(grid *models.Grid, agents []Agent) (*Solution, error) {
+	if err := validateAgents(grid, agents); err != nil {
+		return nil, err
+	}
+	if c.Window < 0 {
+		return nil, fmt.Errorf("window must not be negative, got %d", c.Window)
+	}
+	goalTrees := make(map[int]*algorithms.ShortestPathTree)
+	for _, agent := range agents {
+		goal, err := grid.GetNode(agent.Goal.X, agent.Goal.Y)
+		if err != nil {
+			return nil, err
+		}
+		if goalTrees[agent.ID], err = algorithms.NewUnweightedShortestPathTree(grid, goal); err != nil {
+			return nil, err
+		}
+	}
+
+	var solution *Solution
+	var err error
+	if c.Window == 0 {
+		solution, err = c.solveCooperative(grid, agents, goalTrees)
+	} else {
+		solution, err = c.solveWindowed(grid, agents, goalTrees)
+	}
+	if err != nil {
+		return nil, err
+	}
+	solution.Cost = pathCost(solution.Paths)
+	solution.Conflicts = FindConflicts(solution.Paths)
+	return solution, nil
+}
+
+func (c *Cooperative) solveCooperative(grid *models.Grid, agents []Agent, goalTrees map[int]*algorithms.ShortestPathTree) (*Solution, error) {
+	solution := &Solution{Paths: make(map[int][]Step)}
+	table := newReservationTable()
+	alone, err := planningHorizon(grid, goalTrees, agents)
+	if err != nil {
+		return nil, err
+	}
+	// Leave room for every agent planned so far to pass through before giving up
+	horizon := alone
+	for i, agent := range agents {
+		path, err := spaceTimeSearch(grid, goalTrees[agent.ID], agent.Start, 0, agent.Goal, reservationView{table, agent.ID}, horizon, 0)
+		if err != nil {
+			if path, err = spaceTimeSearch(grid, goalTrees[agent.ID], agent.Start, 0, agent.Goal, noReservations{}, alone, 0); err != nil {
+				return nil, fmt.Errorf("agent %d: %w", agent.ID, err)
+			}
+		}
+		table.reserve(agent.ID, path, true)
+		horizon += len(path)
+		solution.Paths[agent.ID] = path
+		solution.Snapshots = append(solution.Snapshots, c.snapshot(i, solution.Paths))
+	}
+	return solution, nil
+}
+
+func (c *Cooperative) solveWindowed(grid *models.Grid, agents []Agent, goalTrees map[int]*algorithms.ShortestPathTree) (*Solution, error) {
+	solution := &Solution{Paths: make(map[int][]Step)}
+	for _, agent := range agents {
+		solution.Paths[agent.ID] = []Step{{agent.Start.X, agent.Start.Y, 0}}
+	}
+	execute := max(1, c.Window/2)
+	maxT, err := planningHorizon(grid, goalTrees, agents)
+	if err != nil {
+		return nil, err
+	}
+
+	for t, cycle := 0, 0; ; t, cycle = t+execute, cycle+1 {
+		if t > maxT {
+			return nil, fmt.Errorf("agents did not reach their goals within %d steps", maxT)
+		}
+		// Every cycle starts with an empty table, the agents only coordinate over the coming window
+		table := newReservationTable()
+		done := true
+		for _, agent := range agents {
+			path := solution.Paths[agent.ID]
+			position := Cell{path[len(path)-1].X, path[len(path)-1].Y}
+			planned, err := spaceTimeSearch(grid, goalTrees[agent.ID], position, t, agent.Goal, reservationView{table, agent.ID}, t+c.Window, c.Window)
+			if err != nil {
+				if planned, err = spaceTimeSearch(grid, goalTrees[agent.ID], position, t, agent.Goal, noReservations{}, t+c.Window, c.Window); err != nil {
+					return nil, fmt.Errorf("agent %d: %w", agent.ID, err)
+				}
+			}
+			// Agents that arrive early wait at their goal for the rest of the window
+			for last := planned[len(planned)-1]; last.T < t+c.Window; {
+				last.T++
+				planned = append(planned, last)
+			}
+			table.reserve(agent.ID, planned, false)
+			solution.Paths[agent.ID] = append(path, planned[1:execute+1]...)
+			final := planned[execute]
+			if final.X != agent.Goal.X || final.Y != agent.Goal.Y {
+				done = false
+			}
+		}
+		solution.Snapshots = append(solution.Snapshots, c.snapshot(cycle, solution.Paths))
+		if done {
+			break
+		}
+	}
+
+	for id, path := range solution.Paths {
+		solution.Paths[id] = trimWaits(path)
+	}
+	return solution, nil
+}
+
+func (c *Cooperative) snapshot(node int, paths map[int][]Step) Snapshot {
+	copied := make(map[int][]Step, len(paths))
+	for id, path := range paths {
+		copied[id] = path[:len(path):len(path)]
+	}
+	return Snapshot{
+		Node:   node,
+		Parent: node - 1,
+		Cost:   pathCost(copied),
+		Paths:  copied,
+	}
+}
@@ -0,0 +1,160 @@
+package multiagent
+
+import (
+	"errors"
+	"fmt"
+	"pathfinding-algorithms/models"
+	"sort"
+)
+
+// Cell is a location on the grid
+type Cell struct{ X, Y int }
+
+// Step is the location of an agent at time T
+type Step struct{ X, Y, T int }
+
+// Agent moves from Start to Goal, sharing the grid with every other agent
+type Agent struct {
+	ID          int
+	Start, Goal Cell
+}
+
+// Conflict is a collision between two agents. A vertex conflict means both occupy To at time T, an edge
+// conflict means they swap From and To between T-1 and T.
+type Conflict struct {
+	AgentA, AgentB int
+	From, To       Cell
+	T              int
+	Edge           bool
+}
+
+// Constraint forbids an agent from being at To at time T, or from moving From -> To arriving at T
+type Constraint struct {
+	Agent    int
+	From, To Cell
+	T        int
+	Edge     bool
+}
+
+// Snapshot records one step of a planner, such as a constraint tree node expanded by CBS
+type Snapshot struct {
+	Node, Parent int
+	Cost         int
+	Constraints  []Constraint
+	Conflict     *Conflict // The conflict found in the paths, nil once they are collision free
+	Paths        map[int][]Step
+}
+
+// Solution holds the timed path of every agent by ID
+type Solution struct {
+	Paths     map[int][]Step
+	Cost      int        // Sum of the arrival times of all agents
+	Conflicts []Conflict // Collisions left in the paths, always empty for optimal planners
+	Snapshots []Snapshot
+}
+
+// Planner finds collision free paths for several agents on one grid
+type Planner interface {
+	Solve(grid *models.Grid, agents []Agent) (*Solution, error)
+}
+
+// validateAgents checks that every agent starts and ends on an open cell and that no two agents share one
+func validateAgents(grid *models.Grid, agents []Agent) error {
+	if grid == nil {
+		return errors.New("grid is nil")
+	}
+	if len(agents) == 0 {
+		return errors.New("no agents to plan")
+	}
+	starts := make(map[Cell]bool)
+	goals := make(map[Cell]bool)
+	for _, agent := range agents {
+		for _, cell := range []Cell{agent.Start, agent.Goal} {
+			node, err := grid.GetNode(cell.X, cell.Y)
+			if err != nil {
+				return fmt.Errorf("agent %d: %w", agent.ID, err)
+			}
+			if node.IsWall {
+				return fmt.Errorf("agent %d: (%d,%d) is a wall", agent.ID, cell.X, cell.Y)
+			}
+		}
+		if starts[agent.Start] {
+			return fmt.Errorf("agent %d: start is shared with another agent", agent.ID)
+		}
+		if goals[agent.Goal] {
+			return fmt.Errorf("agent %d: goal is shared with another agent", agent.ID)
+		}
+		starts[agent.Start] = true
+		goals[agent.Goal] = true
+	}
+	return nil
+}
+
+// positionAt returns where an agent following path is at time t, agents wait at their last cell
+func positionAt(path []Step, t int) Cell {
+	if t >= len(path) {
+		t = len(path) - 1
+	}
+	return Cell{path[t].X, path[t].Y}
+}
+
+// sortedIDs returns the agent IDs of paths in ascending order so results are deterministic
+func sortedIDs(paths map[int][]Step) []int {
+	ids := make([]int, 0, len(paths))
+	for id := range paths {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// FindConflicts returns every vertex and edge collision between the given paths
+func FindConflicts(paths map[int][]Step) []Conflict {
+	return findConflicts(paths, false)
+}
+
+func findConflicts(paths map[int][]Step, firstOnly bool) []Conflict {
+	ids := sortedIDs(paths)
+	horizon := 0
+	for _, path := range paths {
+		horizon = max(horizon, len(path))
+	}
+
+	var conflicts []Conflict
+	for t := 0; t < horizon; t++ {
+		for i, a := range ids {
+			for _, b := range ids[i+1:] {
+				cellA, cellB := positionAt(paths[a], t), positionAt(paths[b], t)
+				if cellA == cellB {
+					conflicts = append(conflicts, Conflict{AgentA: a, AgentB: b, To: cellA, T: t})
+				} else if t > 0 && cellA == positionAt(paths[b], t-1) && cellB == positionAt(paths[a], t-1) {
+					conflicts = append(conflicts, Conflict{AgentA: a, AgentB: b, From: cellB, To: cellA, T: t, Edge: true})
+				} else {
+					continue
+				}
+				if firstOnly {
+					return conflicts
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// pathCost is the sum of arrival times, trailing waits at the goal are free
+func pathCost(paths map[int][]Step) int {
+	cost := 0
+	for _, path := range paths {
+		cost += len(trimWaits(path)) - 1
+	}
+	return cost
+}
+
+// trimWaits drops the steps an agent spends waiting at the end of its path
+func trimWaits(path []Step) []Step {
+	last := len(path) - 1
+	for last > 0 && path[last].X == path[last-1].X && path[last].Y == path[last-1].Y {
+		last--
+	}
+	return path[:last+1]
+}
@@ -0,0 +1,118 @@
+package multiagent_test
+
+import (
+	"pathfinding-algorithms/models"
+	"pathfinding-algorithms/multiagent"
+	"testing"
+)
+
+func TestCBSResolvesHeadOnCollision(t *testing.T) {
+	grid, _ := models.NewGrid(10, 10)
+	agents := []multiagent.Agent{
+		{ID: 0, Start: multiagent.Cell{X: 1, Y: 5}, Goal: multiagent.Cell{X: 8, Y: 5}},
+		{ID: 1, Start: multiagent.Cell{X: 8, Y: 5}, Goal: multiagent.Cell{X: 1, Y: 5}},
+	}
+
+	solution, err := (&multiagent.CBS{}).Solve(grid, agents)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if conflicts := multiagent.FindConflicts(solution.Paths); len(conflicts) != 0 {
+		t.Fatalf("Solution has conflicts: %v", conflicts)
+	}
+	// One agent goes straight, the other steps around it
+	if solution.Cost != 16 {
+		t.Fatalf("Expected an optimal cost of 16, got %d", solution.Cost)
+	}
+	if len(solution.Snapshots) < 2 {
+		t.Fatalf("Expected the constraint tree to branch, got %d snapshots", len(solution.Snapshots))
+	}
+}
+
+func TestCBSWaitsInCorridor(t *testing.T) {
+	grid, _ := models.NewGrid(10, 10)
+	// A single-lane corridor on row 5 with a side pocket at (4,4)
+	for y := 1; y < 9; y++ {
+		for x := 1; x < 9; x++ {
+			if y != 5 && !(x == 4 && y == 4) {
+				grid.SetWall(x, y, true)
+			}
+		}
+	}
+	agents := []multiagent.Agent{
+		{ID: 0, Start: multiagent.Cell{X: 1, Y: 5}, Goal: multiagent.Cell{X: 8, Y: 5}},
+		{ID: 1, Start: multiagent.Cell{X: 8, Y: 5}, Goal: multiagent.Cell{X: 1, Y: 5}},
+	}
+
+	solution, err := (&multiagent.CBS{}).Solve(grid, agents)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if conflicts := multiagent.FindConflicts(solution.Paths); len(conflicts) != 0 {
+		t.Fatalf("Solution has conflicts: %v", conflicts)
+	}
+	for id, path := range solution.Paths {
+		for i, step := range path {
+			if step.T != i {
+				t.Fatalf("Path of agent %d is not timed consecutively: %v", id, path)
+			}
+		}
+	}
+}
+
+func TestCBSIgnoresWeightsOnWeightedGrid(t *testing.T) {
+	grid, _ := models.NewGrid(10, 10)
+	// Agents move one cell per timestep, weights must not make the heuristic overestimate the steps left
+	for x := 2; x <= 7; x++ {
+		if err := grid.SetWeight(x, 5, 9); err != nil {
+			t.Fatalf("SetWeight failed: %v", err)
+		}
+	}
+	agents := []multiagent.Agent{
+		{ID: 0, Start: multiagent.Cell{X: 1, Y: 5}, Goal: multiagent.Cell{X: 8, Y: 5}},
+	}
+
+	solution, err := (&multiagent.CBS{}).Solve(grid, agents)
+	if err != nil {
+		t.Fatalf("Solve failed: %v", err)
+	}
+	if solution.Cost != 7 {
+		t.Fatalf("Expected an optimal cost of 7, got %d", solution.Cost)
+	}
+}
+
+func crossingAgents() []multiagent.Agent {
+	return []multiagent.Agent{
+		{ID: 0, Start: multiagent.Cell{X: 1, Y: 5}, Goal: multiagent.Cell{X: 8, Y: 5}},
+		{ID: 1, Start: multiagent.Cell{X: 8, Y: 5}, Goal: multiagent.Cell{X: 1, Y: 5}},
+		{ID: 2, Start: multiagent.Cell{X: 5, Y: 1}, Goal: multiagent.Cell{X: 5, Y: 8}},
+		{ID: 3, Start: multiagent.Cell{X: 5, Y: 8}, Goal: multiagent.Cell{X: 5, Y: 1}},
+	}
+}
+
+func TestCooperativePlannersAvoidCollisions(t *testing.T) {
+	grid, _ := models.NewGrid(10, 10)
+	optimal, err := (&multiagent.CBS{}).Solve(grid, crossingAgents())
+	if err != nil {
+		t.Fatalf("CBS failed: %v", err)
+	}
+
+	for _, window := range []int{0, 4, 8} {
+		solution, err := (&multiagent.Cooperative{Window: window}).Solve(grid, crossingAgents())
+		if err != nil {
+			t.Fatalf("Window %d: Solve failed: %v", window, err)
+		}
+		if len(solution.Conflicts) != 0 {
+			t.Fatalf("Window %d: solution has conflicts: %v", window, solution.Conflicts)
+		}
+		if solution.Cost < optimal.Cost {
+			t.Fatalf("Window %d: cost %d is below the optimum %d", window, solution.Cost, optimal.Cost)
+		}
+		for id, path := range solution.Paths {
+			goal := crossingAgents()[id].Goal
+			if last := path[len(path)-1]; last.X != goal.X || last.Y != goal.Y {
+				t.Fatalf("Window %d: agent %d ended at (%d,%d)", window, id, last.X, last.Y)
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package multiagent
+
+import (
+	"errors"
+	"math"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/models"
+)
+
+// blocker tells the space-time search which moves other agents have claimed
+type blocker interface {
+	// isBlocked reports whether moving from one cell to another, arriving at time t, is forbidden
+	isBlocked(from, to Cell, t int) bool
+	// lastBlocked returns the last time the cell is blocked, -1 if never, an agent may only rest there after it
+	lastBlocked(cell Cell) int
+}
+
+// horizonFactor is how many times the steps of the longest single-agent path the agents are planned for.
+// Waiting for the others rarely takes longer, and without a bound an unsolvable branch keeps searching through
+// W*H timesteps before giving up.
+const horizonFactor = 4
+
+// planningHorizon returns the number of timesteps the agents are planned within, horizonFactor times the
+// longest distance of an agent to its goal when the others are ignored
+func planningHorizon(grid *models.Grid, goalTrees map[int]*algorithms.ShortestPathTree, agents []Agent) (int, error) {
+	longest := 1
+	for _, agent := range agents {
+		start, err := grid.GetNode(agent.Start.X, agent.Start.Y)
+		if err != nil {
+			return 0, err
+		}
+		if distance := goalTrees[agent.ID].Distance(start); !math.IsInf(distance, 1) {
+			longest = max(longest, int(distance))
+		}
+	}
+	return horizonFactor * longest, nil
+}
+
+type state struct {
+	cell Cell
+	t    int
+}
+
+// spaceTimeSearch runs A* over (cell, time) states from start at startT to goal, moving to a neighbour or
+// waiting each step. The distances in goalTree, which ignore other agents, are used as the heuristic, it has to
+// count steps rather than weighted costs to stay admissible. With a
+// window greater than 0 the search stops after that many steps, returning the partial path.
+func spaceTimeSearch(grid *models.Grid, goalTree *algorithms.ShortestPathTree, start Cell, startT int, goal Cell, blocked blocker, maxT, window int) ([]Step, error) {
+	startNode, err := grid.GetNode(start.X, start.Y)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(goalTree.Distance(startNode), 1) {
+		return nil, errors.New("goal is unreachable")
+	}
+
+	// Prefer deeper states on ties, they are closer to the goal
+	openSet := &datastructures.PriorityQueue[state]{}
+	if err := openSet.SetTieBreak(datastructures.LargerG, 0); err != nil {
+		return nil, err
+	}
+	openSet.Init()
+	first := state{start, startT}
+	gScore := map[state]float64{first: 0}
+	parents := make(map[state]state)
+	closedSet := make(map[state]bool)
+	openSet.Insert(datastructures.NewScoredItem(first, goalTree.Distance(startNode), 0, goalTree.Distance(startNode)))
+	restAfter := blocked.lastBlocked(goal)
+
+	for openSet.Len() > 0 {
+		current := openSet.PopMin().GetValue()
+		closedSet[current] = true
+
+		atGoal := current.cell == goal && current.t > restAfter
+		if atGoal || (window > 0 && current.t-startT >= window) {
+			path := []Step{{current.cell.X, current.cell.Y, current.t}}
+			for at := current; at != first; {
+				at = parents[at]
+				path = append(path, Step{at.cell.X, at.cell.Y, at.t})
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, nil
+		}
+		if current.t >= maxT {
+			continue
+		}
+
+		node, err := grid.GetNode(current.cell.X, current.cell.Y)
+		if err != nil {
+			return nil, err
+		}
+		neighbors, err := grid.GetNeighbors(node)
+		if err != nil {
+			return nil, err
+		}
+		// Waiting in place is always an option
+		neighbors = append(neighbors, node)
+		for _, neighbor := range neighbors {
+			next := state{Cell{neighbor.X, neighbor.Y}, current.t + 1}
+			if neighbor.IsWall || closedSet[next] || blocked.isBlocked(current.cell, next.cell, next.t) {
+				continue
+			}
+			h := goalTree.Distance(neighbor)
+			if math.IsInf(h, 1) {
+				continue
+			}
+			g := gScore[current] + 1
+			if known, exists := gScore[next]; exists && g >= known {
+				continue
+			}
+			gScore[next] = g
+			parents[next] = current
+			if !openSet.Contains(next) {
+				openSet.Insert(datastructures.NewScoredItem(next, g+h, g, h))
+			} else {
+				openSet.UpdateScored(next, g+h, g, h)
+			}
+		}
+	}
+	return nil, errors.New("no path within the time limit")
+}
@@ -0,0 +1,144 @@
+package pathfinder
+
+import (
+	"errors"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/multiagent"
+)
+
+type Planner int
+
+const (
+	conflictBasedSearch Planner = iota
+	cooperativeAStar
+	windowedCooperativeAStar
+)
+
+const defaultAgentWindow = 8
+
+// AddAgent adds an agent moving from (startX, startY) to (goalX, goalY) and returns its ID
+func (p *Pathfinder) AddAgent(startX, startY, goalX, goalY int) (int, error) {
+	if p.activeAlgorithm == nil {
+		return 0, errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return 0, err
+	}
+	for _, location := range []multiagent.Cell{{X: startX, Y: startY}, {X: goalX, Y: goalY}} {
+		node, err := grid.GetNode(location.X, location.Y)
+		if err != nil {
+			return 0, err
+		}
+		if node.IsWall {
+			return 0, errors.New("invalid location")
+		}
+	}
+	agent := multiagent.Agent{
+		ID:    p.nextAgentID,
+		Start: multiagent.Cell{X: startX, Y: startY},
+		Goal:  multiagent.Cell{X: goalX, Y: goalY},
+	}
+	p.nextAgentID++
+	p.agents = append(p.agents, agent)
+	p.resetAgentSolution()
+	return agent.ID, nil
+}
+
+func (p *Pathfinder) RemoveAgent(id int) error {
+	for i, agent := range p.agents {
+		if agent.ID == id {
+			p.agents = append(p.agents[:i], p.agents[i+1:]...)
+			p.resetAgentSolution()
+			return nil
+		}
+	}
+	return errors.New("agent not found")
+}
+
+func (p *Pathfinder) ClearAgents() {
+	p.agents = nil
+	p.resetAgentSolution()
+}
+
+func (p *Pathfinder) GetAgents() []multiagent.Agent {
+	return p.agents
+}
+
+func (p *Pathfinder) resetAgentSolution() {
+	p.agentSolution = nil
+	p.agentSnapshots = &datastructures.Queue[multiagent.Snapshot]{}
+}
+
+// SolveAgents plans every agent on the active grid with the given multi-agent planner
+func (p *Pathfinder) SolveAgents(planner Planner) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	plannerFunc, exists := p.plannersMap[planner]
+	if !exists {
+		return errors.New("planner not found")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return err
+	}
+	p.resetAgentSolution()
+	solution, err := plannerFunc(p.agentWindow).Solve(grid, p.agents)
+	if err != nil {
+		return err
+	}
+	p.agentSolution = solution
+	for _, snapshot := range solution.Snapshots {
+		p.agentSnapshots.Enqueue(snapshot)
+	}
+	return nil
+}
+
+// SetAgentWindow sets how many steps windowed planners look ahead
+func (p *Pathfinder) SetAgentWindow(window int) error {
+	if window < 1 {
+		return errors.New("window must be at least 1")
+	}
+	p.agentWindow = window
+	return nil
+}
+
+// GetAgentPath returns the timed path of an agent from the last solution
+func (p *Pathfinder) GetAgentPath(id int) ([]multiagent.Step, error) {
+	if p.agentSolution == nil {
+		return nil, errors.New("agents are not solved")
+	}
+	path, exists := p.agentSolution.Paths[id]
+	if !exists {
+		return nil, errors.New("agent not found")
+	}
+	return path, nil
+}
+
+func (p *Pathfinder) GetAgentSolution() (*multiagent.Solution, error) {
+	if p.agentSolution == nil {
+		return nil, errors.New("agents are not solved")
+	}
+	return p.agentSolution, nil
+}
+
+// GetAgentConflicts returns the collisions left in the last solution
+func (p *Pathfinder) GetAgentConflicts() ([]multiagent.Conflict, error) {
+	if p.agentSolution == nil {
+		return nil, errors.New("agents are not solved")
+	}
+	return p.agentSolution.Conflicts, nil
+}
+
+// GetAgentSnapshot returns the next step of the planner, nil when all have been retrieved
+func (p *Pathfinder) GetAgentSnapshot() (*multiagent.Snapshot, error) {
+	if p.agentSolution == nil {
+		return nil, errors.New("agents are not solved")
+	}
+	if p.agentSnapshots.IsEmpty() {
+		return nil, nil
+	}
+	snapshot, _ := p.agentSnapshots.Dequeue()
+	return &snapshot, nil
+}
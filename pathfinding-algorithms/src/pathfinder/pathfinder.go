@@ -4,7 +4,11 @@ import (
 	"errors"
 	"math/rand"
 	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/datastructures"
+	"pathfinding-algorithms/mazes"
 	"pathfinding-algorithms/models"
+	"pathfinding-algorithms/multiagent"
+	"time"
 )
 
 type Algorithm int
@@ -12,11 +16,25 @@ type Algorithm int
 const (
 	aStar Algorithm = iota
 	dijkstra
+	hpaStar
 )
 
 type Pathfinder struct {
 	algorithmsMap   map[Algorithm]func() algorithms.PathfindingAlgorithm
 	activeAlgorithm algorithms.PathfindingAlgorithm
+	activeID        Algorithm
+	settings        map[Algorithm]searchSettings
+	plannersMap     map[Planner]func(window int) multiagent.Planner
+	agentWindow     int
+	agents          []multiagent.Agent
+	nextAgentID     int
+	agentSolution   *multiagent.Solution
+	agentSnapshots  *datastructures.Queue[multiagent.Snapshot]
+	tourGoals       []models.Point
+	tour            *algorithms.Tour
+	pursuit         *algorithms.Pursuit
+	targetInterval  int
+	seeds           rand.Source // Draws the seeds of generations asked for with seed 0
 }
 
 // NewPathfinder creates a new Pathfinder instance
@@ -29,10 +47,35 @@ func NewPathfinder() *Pathfinder {
 			dijkstra: func() algorithms.PathfindingAlgorithm {
 				return &algorithms.Dijkstra{}
 			},
+			hpaStar: func() algorithms.PathfindingAlgorithm {
+				return &algorithms.HPAStar{}
+			},
+		},
+		plannersMap: map[Planner]func(window int) multiagent.Planner{
+			conflictBasedSearch: func(int) multiagent.Planner {
+				return &multiagent.CBS{}
+			},
+			cooperativeAStar: func(int) multiagent.Planner {
+				return &multiagent.Cooperative{}
+			},
+			windowedCooperativeAStar: func(window int) multiagent.Planner {
+				return &multiagent.Cooperative{Window: window}
+			},
 		},
+		settings:       make(map[Algorithm]searchSettings),
+		agentWindow:    defaultAgentWindow,
+		agentSnapshots: &datastructures.Queue[multiagent.Snapshot]{},
+		seeds:          rand.NewSource(time.Now().UnixNano()),
 	}
 }
 
+// searchSettings are the choices made for an algorithm, they are kept when switching to another algorithm and back
+type searchSettings struct {
+	tieBreak datastructures.TieBreak
+	tieSeed  int64
+	openSet  datastructures.OpenSetID
+}
+
 // SetActiveAlgorithm sets the active algorithm based on the name, resets the grid everytime
 func (p *Pathfinder) SetActiveAlgorithm(algorithm Algorithm, width, height int) error {
 	algFunc, exists := p.algorithmsMap[algorithm]
@@ -41,7 +84,75 @@ func (p *Pathfinder) SetActiveAlgorithm(algorithm Algorithm, width, height int)
 	}
 
 	p.activeAlgorithm = algFunc()
-	return p.activeAlgorithm.Init(width, height)
+	p.activeID = algorithm
+	if err := p.activeAlgorithm.Init(width, height); err != nil {
+		return err
+	}
+	settings, ok := p.settings[algorithm]
+	if !ok {
+		return nil
+	}
+	if tieBreaking, ok := p.activeAlgorithm.(algorithms.TieBreakingAlgorithm); ok {
+		if err := tieBreaking.SetTieBreak(settings.tieBreak, settings.tieSeed); err != nil {
+			return err
+		}
+	}
+	if openSetAlgorithm, ok := p.activeAlgorithm.(algorithms.OpenSetAlgorithm); ok {
+		return openSetAlgorithm.SetOpenSet(settings.openSet)
+	}
+	return nil
+}
+
+// configure returns the active instance of the algorithm, or a new one to validate settings on while it is not active
+func (p *Pathfinder) configure(algorithm Algorithm) (algorithms.PathfindingAlgorithm, error) {
+	algFunc, exists := p.algorithmsMap[algorithm]
+	if !exists {
+		return nil, errors.New("algorithm not found")
+	}
+	if p.activeAlgorithm != nil && p.activeID == algorithm {
+		return p.activeAlgorithm, nil
+	}
+	return algFunc(), nil
+}
+
+// SetTieBreak selects how the algorithm orders open nodes with equal priority, seed is used by random tie
+// breaking. The choice applies to the next search and whenever the algorithm is made active again.
+func (p *Pathfinder) SetTieBreak(algorithm Algorithm, tieBreak datastructures.TieBreak, seed int64) error {
+	instance, err := p.configure(algorithm)
+	if err != nil {
+		return err
+	}
+	tieBreaking, ok := instance.(algorithms.TieBreakingAlgorithm)
+	if !ok {
+		return errors.New("algorithm does not support tie breaking")
+	}
+	if err := tieBreaking.SetTieBreak(tieBreak, seed); err != nil {
+		return err
+	}
+	settings := p.settings[algorithm]
+	settings.tieBreak, settings.tieSeed = tieBreak, seed
+	p.settings[algorithm] = settings
+	return nil
+}
+
+// SetOpenSet selects the data structure the algorithm keeps its open nodes in, like SetTieBreak it applies to
+// the next search and is kept for the algorithm
+func (p *Pathfinder) SetOpenSet(algorithm Algorithm, openSet datastructures.OpenSetID) error {
+	instance, err := p.configure(algorithm)
+	if err != nil {
+		return err
+	}
+	openSetAlgorithm, ok := instance.(algorithms.OpenSetAlgorithm)
+	if !ok {
+		return errors.New("algorithm does not support other open sets")
+	}
+	if err := openSetAlgorithm.SetOpenSet(openSet); err != nil {
+		return err
+	}
+	settings := p.settings[algorithm]
+	settings.openSet = openSet
+	p.settings[algorithm] = settings
+	return nil
 }
 
 func (p *Pathfinder) SetStart(x, y int) error {
@@ -112,21 +223,22 @@ func (p *Pathfinder) GetDimensions() (width int, height int, err error) {
 	return len(nodes[0]), len(nodes), nil
 }
 
-func (p *Pathfinder) GetSnapshot() ([][]*models.Node, error) {
+func (p *Pathfinder) GetSnapshot() (*models.Snapshot, error) {
 	if p.activeAlgorithm == nil {
 		return nil, errors.New("no active algorithm set")
 	}
 	return p.activeAlgorithm.GetSnapshot()
 }
 
-func (p *Pathfinder) GetPath() (map[models.Node]models.Node, error) {
+// GetPath returns the route from the start through every waypoint to the end
+func (p *Pathfinder) GetPath() ([]models.Node, error) {
 	if p.activeAlgorithm == nil {
 		return nil, errors.New("no active algorithm set")
 	}
-	if grid, err := p.activeAlgorithm.GetPath(); err != nil {
+	if path, err := p.activeAlgorithm.GetPath(); err != nil {
 		return nil, err
 	} else {
-		return grid, nil
+		return path, nil
 	}
 }
 
@@ -152,56 +264,232 @@ func (p *Pathfinder) GetEnd() (node *models.Node, err error) {
 	}
 }
 
-func (p *Pathfinder) GenerateMaze() error {
-	maze, err := p.GetNodes()
+// SetWeight sets the weight of the node at (x, y), 0 or 1 for a normal node. A step costs the average weight of
+// the two nodes it joins, for every algorithm.
+func (p *Pathfinder) SetWeight(x, y, weight int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
 	if err != nil {
 		return err
 	}
-	for _, row := range maze {
-		for _, node := range row {
-			if !node.IsStart && !node.IsEnd {
-				node.IsWall = true
-			}
-		}
+	if err := grid.SetWeight(x, y, weight); err != nil {
+		return err
+	}
+	return p.invalidate()
+}
+
+func (p *Pathfinder) AddEnd(x, y int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.AddEnd(x, y)
+}
+
+func (p *Pathfinder) RemoveEnd(x, y int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.RemoveEnd(x, y)
+}
+
+func (p *Pathfinder) GetEnds() ([]*models.Node, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.GetEnds()
+}
+
+// GetReachedEnd returns the end node the path finished at, the closest of the ends
+func (p *Pathfinder) GetReachedEnd() (*models.Node, error) {
+	path, err := p.GetPath()
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, errors.New("no path found")
+	}
+	last := path[len(path)-1]
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return nil, err
+	}
+	return grid.GetNode(last.X, last.Y)
+}
+
+func (p *Pathfinder) AddWaypoint(x, y int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.AddWaypoint(x, y)
+}
+
+func (p *Pathfinder) RemoveWaypoint(x, y int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.RemoveWaypoint(x, y)
+}
+
+func (p *Pathfinder) GetWaypoints() ([]*models.Node, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	return p.activeAlgorithm.GetWaypoints()
+}
+
+// GetHierarchy returns the abstraction of the active algorithm, if it searches one
+func (p *Pathfinder) GetHierarchy() (*algorithms.Hierarchy, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	hierarchical, ok := p.activeAlgorithm.(algorithms.HierarchicalAlgorithm)
+	if !ok {
+		return nil, errors.New("active algorithm is not hierarchical")
+	}
+	return hierarchical.GetHierarchy()
+}
+
+// GetFlowField computes the cost and direction towards the end node for every cell of the grid
+func (p *Pathfinder) GetFlowField() (*algorithms.FlowField, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
 	}
-	if err := p.generateMaze(maze, 2, 2); err != nil {
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return nil, err
+	}
+	return algorithms.NewFlowField(grid)
+}
+
+// landmarkAlgorithm returns the active algorithm if it supports the ALT heuristic
+func (p *Pathfinder) landmarkAlgorithm() (algorithms.LandmarkAlgorithm, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	landmarkAlgorithm, ok := p.activeAlgorithm.(algorithms.LandmarkAlgorithm)
+	if !ok {
+		return nil, errors.New("active algorithm does not support landmarks")
+	}
+	return landmarkAlgorithm, nil
+}
+
+// SetLandmarks uses count landmarks selected by farthest-point for the ALT heuristic
+func (p *Pathfinder) SetLandmarks(count int) error {
+	landmarkAlgorithm, err := p.landmarkAlgorithm()
+	if err != nil {
 		return err
 	}
-	for _, row := range maze {
-		for _, node := range row {
-			node.Visited = false
-		}
+	return landmarkAlgorithm.SelectLandmarks(count)
+}
+
+func (p *Pathfinder) AddLandmark(x, y int) error {
+	landmarkAlgorithm, err := p.landmarkAlgorithm()
+	if err != nil {
+		return err
 	}
-	return nil
+	return landmarkAlgorithm.AddLandmark(x, y)
 }
 
-func (p *Pathfinder) generateMaze(maze [][]*models.Node, x, y int) error {
-	directions := []models.Point{
-		{0, -1},
-		{-1, 0}, {1, 0},
-		{0, 1},
+func (p *Pathfinder) ClearLandmarks() error {
+	landmarkAlgorithm, err := p.landmarkAlgorithm()
+	if err != nil {
+		return err
 	}
-	// Mark the current cell as visited
+	return landmarkAlgorithm.ClearLandmarks()
+}
 
-	maze[y][x].IsWall = false
-	maze[y][x].Visited = true
+func (p *Pathfinder) GetLandmarks() ([]*models.Node, error) {
+	landmarkAlgorithm, err := p.landmarkAlgorithm()
+	if err != nil {
+		return nil, err
+	}
+	return landmarkAlgorithm.GetLandmarks()
+}
 
-	// Randomly order the directions
-	rand.Shuffle(len(directions), func(i, j int) {
-		directions[i], directions[j] = directions[j], directions[i]
-	})
+// SetSeedSource replaces the source that seeds are drawn from when a generation is asked for with seed 0
+func (p *Pathfinder) SetSeedSource(source rand.Source) error {
+	if source == nil {
+		return errors.New("seed source is nil")
+	}
+	p.seeds = source
+	return nil
+}
 
-	// Explore the neighbors in a random order
-	for _, d := range directions {
-		nx, ny := x+2*d.Dx, y+2*d.Dy
+// GenerateMaze turns the active grid into a maze using the generator registered under generatorID, then
+// removes the braid fraction of its dead ends to add loops. A seed of 0 picks a new one, the seed used is
+// returned so the same maze can be generated again.
+func (p *Pathfinder) GenerateMaze(generatorID mazes.GeneratorID, seed int64, braid float64) (int64, error) {
+	generator, err := mazes.New(generatorID)
+	if err != nil {
+		return 0, err
+	}
+	return p.generate(generator, seed, braid)
+}
 
-		// Check bounds and if the neighbor has been visited
-		if nx >= 0 && nx < len(maze[0]) && ny >= 0 && ny < len(maze) && !maze[ny][nx].Visited {
-			maze[(y+ny)/2][(x+nx)/2].IsWall = false
-			if err := p.generateMaze(maze, nx, ny); err != nil {
-				return err
-			} // Recursively visit the neighbor
+// GenerateTerrain fills the active grid with weighted noise terrain, scale is the size of the largest features
+// in nodes and every octave adds finer detail. Seeds work as in GenerateMaze.
+func (p *Pathfinder) GenerateTerrain(seed int64, scale float64, octaves int) (int64, error) {
+	terrain := mazes.NewTerrain()
+	terrain.Scale, terrain.Octaves = scale, octaves
+	return p.generate(terrain, seed, 0)
+}
+
+func (p *Pathfinder) generate(generator mazes.MazeGenerator, seed int64, braid float64) (int64, error) {
+	if p.activeAlgorithm == nil {
+		return 0, errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return 0, err
+	}
+	// Animated generators queue their construction steps as snapshots of the active algorithm
+	if animated, ok := generator.(mazes.AnimatedGenerator); ok {
+		if recorder, ok := p.activeAlgorithm.(algorithms.SnapshotRecorder); ok {
+			animated.SetRecorder(recorder)
 		}
 	}
+	for seed == 0 {
+		seed = p.seeds.Int63()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	if err := generator.Generate(grid, rng); err != nil {
+		return 0, err
+	}
+	if err := mazes.Braid(grid, rng, braid); err != nil {
+		return 0, err
+	}
+	if err := grid.ClearVisited(); err != nil {
+		return 0, err
+	}
+	if err := p.invalidate(); err != nil {
+		return 0, err
+	}
+	return seed, nil
+}
+
+// AnalyzeMaze measures the structure and difficulty of the maze on the active grid
+func (p *Pathfinder) AnalyzeMaze() (*mazes.Metrics, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return nil, err
+	}
+	return mazes.Analyze(grid)
+}
+
+// GetMazeGenerators lists the generators GenerateMaze accepts
+func (p *Pathfinder) GetMazeGenerators() []mazes.Generator {
+	return mazes.List()
+}
+
+// invalidate notifies the active algorithm that the grid was modified directly
+func (p *Pathfinder) invalidate() error {
+	if invalidator, ok := p.activeAlgorithm.(algorithms.GridInvalidator); ok {
+		return invalidator.Invalidate()
+	}
 	return nil
 }
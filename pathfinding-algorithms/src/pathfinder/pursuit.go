@@ -0,0 +1,82 @@
+package pathfinder
+
+import (
+	"errors"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/models"
+)
+
+type TargetPolicy int
+
+const (
+	scriptedTarget TargetPolicy = iota
+	randomWalkTarget
+	evasiveTarget
+)
+
+// defaultTargetInterval lets the target move every other tick, so the hunter is faster and the chase ends
+const defaultTargetInterval = 2
+
+// StartPursuit begins a chase on the active grid. The hunter starts at the start node and the target at the
+// end node, a scripted target patrols back and forth through the waypoints.
+func (p *Pathfinder) StartPursuit(policy TargetPolicy, seed int64) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return err
+	}
+	var targetPolicy algorithms.TargetPolicy
+	switch policy {
+	case scriptedTarget:
+		end, err := grid.GetEnd()
+		if err != nil {
+			return err
+		}
+		waypoints, err := grid.GetWaypoints()
+		if err != nil {
+			return err
+		}
+		if targetPolicy, err = algorithms.NewScriptedTarget(grid, append([]*models.Node{end}, waypoints...)); err != nil {
+			return err
+		}
+	case randomWalkTarget:
+		targetPolicy = algorithms.NewRandomWalkTarget(seed)
+	case evasiveTarget:
+		targetPolicy = algorithms.EvasiveTarget{}
+	default:
+		return errors.New("target policy not found")
+	}
+	interval := p.targetInterval
+	if interval == 0 {
+		interval = defaultTargetInterval
+	}
+	pursuit, err := algorithms.NewPursuit(grid, targetPolicy, interval)
+	if err != nil {
+		return err
+	}
+	p.pursuit = pursuit
+	return nil
+}
+
+// SetTargetInterval sets how many ticks pass between moves of the target, used by the next pursuit
+func (p *Pathfinder) SetTargetInterval(interval int) error {
+	if interval < 1 {
+		return errors.New("target interval must be at least 1")
+	}
+	p.targetInterval = interval
+	return nil
+}
+
+// Tick advances the target and the hunter of the running pursuit by one step
+func (p *Pathfinder) Tick() (*algorithms.PursuitSnapshot, error) {
+	if p.pursuit == nil {
+		return nil, errors.New("no pursuit started")
+	}
+	return p.pursuit.Tick()
+}
+
+func (p *Pathfinder) StopPursuit() {
+	p.pursuit = nil
+}
@@ -0,0 +1,80 @@
+package pathfinder
+
+import (
+	"errors"
+	"pathfinding-algorithms/algorithms"
+	"pathfinding-algorithms/models"
+)
+
+// AddTourGoal adds a cell the tour has to visit, in any order
+func (p *Pathfinder) AddTourGoal(x, y int) error {
+	if p.activeAlgorithm == nil {
+		return errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return err
+	}
+	node, err := grid.GetNode(x, y)
+	if err != nil {
+		return err
+	}
+	if node.IsWall || node.IsStart {
+		return errors.New("invalid location")
+	}
+	for _, goal := range p.tourGoals {
+		if goal.Dx == x && goal.Dy == y {
+			return errors.New("goal already exists")
+		}
+	}
+	p.tourGoals = append(p.tourGoals, models.Point{Dx: x, Dy: y})
+	p.tour = nil
+	return nil
+}
+
+func (p *Pathfinder) RemoveTourGoal(x, y int) error {
+	for i, goal := range p.tourGoals {
+		if goal.Dx == x && goal.Dy == y {
+			p.tourGoals = append(p.tourGoals[:i], p.tourGoals[i+1:]...)
+			p.tour = nil
+			return nil
+		}
+	}
+	return errors.New("goal not found")
+}
+
+func (p *Pathfinder) ClearTourGoals() {
+	p.tourGoals = nil
+	p.tour = nil
+}
+
+// SolveTour orders the tour goals, starting from the start node of the active grid
+func (p *Pathfinder) SolveTour() (*algorithms.Tour, error) {
+	if p.activeAlgorithm == nil {
+		return nil, errors.New("no active algorithm set")
+	}
+	grid, err := p.activeAlgorithm.GetGrid()
+	if err != nil {
+		return nil, err
+	}
+	goals := make([]*models.Node, len(p.tourGoals))
+	for i, goal := range p.tourGoals {
+		if goals[i], err = grid.GetNode(goal.Dx, goal.Dy); err != nil {
+			return nil, err
+		}
+	}
+	tour, err := algorithms.SolveTour(grid, goals)
+	if err != nil {
+		return nil, err
+	}
+	p.tour = tour
+	return tour, nil
+}
+
+// GetTour returns the last solved tour, its order refers to the goals in the order they were added
+func (p *Pathfinder) GetTour() (*algorithms.Tour, error) {
+	if p.tour == nil {
+		return nil, errors.New("tour is not solved")
+	}
+	return p.tour, nil
+}
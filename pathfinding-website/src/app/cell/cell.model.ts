@@ -7,6 +7,7 @@ export class Cell {
     public isStart: boolean = false,
     public isEnd: boolean = false,
     public isPath: boolean = false,
-    public visited: boolean = false
+    public visited: boolean = false,
+    public isWaypoint: boolean = false
   ) {}
 }
@@ -48,7 +48,7 @@ export class GridComponent implements OnInit, OnDestroy, OnChanges {
       this.solveGrid();
     } else if (changes["drawGridEvent"]) {
       if (!this.solved) {
-        requestAnimationFrame(this.drawGrid.bind(this));
+        requestAnimationFrame(this.showMazeSnapshots.bind(this));
       }
     }
   }
@@ -155,36 +155,49 @@ export class GridComponent implements OnInit, OnDestroy, OnChanges {
     })
   }
 
+  // Plays the construction steps of animated maze generators, then shows the finished grid
+  showMazeSnapshots(): void {
+    this.wasmService.getSnapshot().then((result) => {
+      if (result.length === 0) {
+        requestAnimationFrame(this.drawGrid.bind(this));
+        return
+      }
+      this.grid = result;
+      const delay = 300 / this.animationSpeed
+      setTimeout(() => requestAnimationFrame(this.showMazeSnapshots.bind(this)), delay)
+    }).catch(() => {
+      requestAnimationFrame(this.drawGrid.bind(this));
+    })
+  }
+
   async showPath(): Promise<void> {
     try {
       const result = await this.wasmService.getPath();
-      const start = await this.wasmService.getStart();
-      if (!start) {
-        console.error("Error getting start");
-        return;
-      }
-
-      const end = await this.wasmService.getEnd();
+      // The search may stop at a closer end than the primary one
+      const end = await this.wasmService.getReachedEnd();
       if (!end) {
-        console.error("Error getting end");
+        console.error("Error getting reached end");
         return;
       }
-      requestAnimationFrame(this.reconstructPath.bind(this, result, start, result.get(end.toString())));
+      requestAnimationFrame(this.drawRoute.bind(this, result, end));
     } catch (error) {
       console.error("An error occurred:", error);
     }
   }
 
-  reconstructPath(path: Map<String, Point>, goal: Point, current: Point | undefined) {
-    if (!current) {
+  // Marks the route in order, leaving the start, waypoints and ends their colours. A route that does not finish at
+  // the end the search reached is stale and left undrawn.
+  drawRoute(path: Point[], end: Point) {
+    const last = path[path.length - 1];
+    if (!last || last.getX() !== end.getX() || last.getY() !== end.getY()) {
       return
-    } else {
-      this.grid[current.getY()][current.getX()] = {...this.grid[current.getY()][current.getX()], isPath: true, visited: false};
-      const next = path.get(current.toString())
-      if (!next || (next.getX() === goal.getX() && next.getY() === goal.getY())) {
-        return
+    }
+    for (const point of path) {
+      const cell = this.grid[point.getY()][point.getX()];
+      if (cell.isStart || cell.isEnd || cell.isWaypoint) {
+        continue
       }
-      this.reconstructPath(path, goal, next);
+      this.grid[point.getY()][point.getX()] = {...cell, isPath: true, visited: false};
     }
   }
 }
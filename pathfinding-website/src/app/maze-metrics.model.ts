@@ -0,0 +1,11 @@
+// Structure of the current maze as reported by analyzeMaze
+export interface MazeMetrics {
+  cells: number;
+  deadEnds: number;
+  solutionLength: number;
+  decisionPoints: number;
+  riverFactor: number;
+  offSolution: number; // Percentage of reachable cells off the solution
+  difficulty: number; // 0 to 100
+  branching: number[]; // Cells by number of open neighbours, 0 to 4
+}
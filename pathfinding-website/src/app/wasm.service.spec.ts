@@ -13,4 +13,10 @@ describe('WasmService', () => {
   it('should be created', () => {
     expect(service).toBeTruthy();
   });
+
+  it('should decode a route that passes a cell twice in order', async () => {
+    // Into a dead end at (1,0) and back out
+    const path = await service.decodePath(new Int32Array([0, 1, 1, 1, 1, 0, 1, 1, 2, 1]));
+    expect(path.map(point => [point.getX(), point.getY()])).toEqual([[0, 1], [1, 1], [1, 0], [1, 1], [2, 1]]);
+  });
 });
@@ -1,5 +1,6 @@
 import {Injectable} from '@angular/core';
 import {Cell} from "./cell/cell.model";
+import {MazeMetrics} from "./maze-metrics.model";
 
 declare var Go: any;
 
@@ -103,6 +104,20 @@ export class WasmService {
     }
   }
 
+  // The end the last path finished at, the closest of several ends rather than the primary one
+  public async getReachedEnd(): Promise<Point> {
+    try {
+      const encoded = await this.executeWasmFunction('getReachedEnd');
+
+      const x: bigint = encoded >> BigInt(32); // Shift right to get the upper 32 bits
+      const y: bigint = encoded & (BigInt(2 ** 32) - BigInt(1)); // Bitwise AND to get the lower 32 bits
+
+      return new Point(Number(x), Number(y));
+    } catch (error) {
+      throw new Error("Reached end node could not be retrieved.");
+    }
+  }
+
   public async setWall(x: number, y: number, isWall: boolean): Promise<boolean> {
     return this.executeWasmFunction('setWall', x, y, isWall);
   }
@@ -115,14 +130,76 @@ export class WasmService {
     return this.executeWasmFunction('clearGrid');
   }
 
-  public async generateMaze(): Promise<boolean> {
-    return this.executeWasmFunction('generateMaze');
+  // Resolves to the seed used, pass it back in to generate the same maze again
+  public async generateMaze(generatorId: number = 0, seed: bigint = BigInt(0), braid: number = 0): Promise<bigint> {
+    return this.executeWasmFunction('generateMaze', generatorId, seed, braid);
+  }
+
+  public async generateTerrain(seed: bigint, scale: number, octaves: number): Promise<bigint> {
+    return this.executeWasmFunction('generateTerrain', seed, scale, octaves);
+  }
+
+  public async getWeights(): Promise<Uint8Array> {
+    const numNodes = await this.getNumNodes();
+    const {memory} = this.wasmModule.instance.exports;
+    const weightsPtr: number = await this.executeWasmFunction('getWeights');
+    if (!weightsPtr) {
+      return new Uint8Array();
+    }
+    return new Uint8Array(memory.buffer, weightsPtr + 16, numNodes).slice();
+  }
+
+  public async analyzeMaze(): Promise<MazeMetrics | null> {
+    const {memory} = this.wasmModule.instance.exports;
+    const metricsPtr: number = await this.executeWasmFunction('analyzeMaze');
+    if (!metricsPtr) {
+      return null;
+    }
+    const values = new Float64Array(memory.buffer, metricsPtr + 16, 12);
+    return {
+      cells: values[0],
+      deadEnds: values[1],
+      solutionLength: values[2],
+      decisionPoints: values[3],
+      riverFactor: values[4],
+      offSolution: values[5],
+      difficulty: values[6],
+      branching: Array.from(values.slice(7)),
+    };
+  }
+
+  public async getMazeGenerators(): Promise<Map<number, string>> {
+    const length: number = await this.executeWasmFunction('getMazeGeneratorsLength');
+    const {memory} = this.wasmModule.instance.exports;
+    const generators = new Map<number, string>();
+    if (!length) {
+      return generators;
+    }
+    const textPtr: number = await this.executeWasmFunction('getMazeGenerators');
+    const text = new TextDecoder().decode(new Uint8Array(memory.buffer, textPtr + 16, length));
+    for (const line of text.split('\n')) {
+      const [id, name] = line.split('\t');
+      if (name) {
+        generators.set(Number(id), name);
+      }
+    }
+    return generators;
   }
 
   public async setActiveAlgorithm(name: number, width: number, height: number): Promise<boolean> {
     return this.executeWasmFunction('setActiveAlgorithm', name, width, height);
   }
 
+  // tieBreak: 0 any, 1 larger g, 2 smaller h, 3 first in, 4 last in, 5 random from the seed
+  public async setTieBreak(algorithm: number, tieBreak: number, seed: bigint = BigInt(0)): Promise<boolean> {
+    return this.executeWasmFunction('setTieBreak', algorithm, tieBreak, seed);
+  }
+
+  // openSet: 0 binary heap, 1 bucket queue, 2 radix heap
+  public async setOpenSet(algorithm: number, openSet: number): Promise<boolean> {
+    return this.executeWasmFunction('setOpenSet', algorithm, openSet);
+  }
+
   public async changeGridSize(width: number, height: number): Promise<boolean> {
     return this.executeWasmFunction('changeGridSize', width, height);
   }
@@ -171,7 +248,7 @@ export class WasmService {
     return this.decodeGrid(grid);
   }
 
-  public async getPath(): Promise<Map<String, Point>> {
+  public async getPath(): Promise<Point[]> {
     const len = await this.getNumPathNodes().catch((error) => {
       console.error("Error getting numNodes", error);
     })
@@ -183,7 +260,7 @@ export class WasmService {
     if (!pathPtr) {
       throw new Error('Failed to get path');
     }
-    const path = new Int32Array(memory.buffer, pathPtr + 16, len * 4);
+    const path = new Int32Array(memory.buffer, pathPtr + 16, len * 2);
     return this.decodePath(path);
   }
 
@@ -224,28 +301,22 @@ export class WasmService {
         const isStart = (encodedNode & (1 << 2)) !== 0;
         const isEnd = (encodedNode & (1 << 3)) !== 0;
         const isPath = (encodedNode & (1 << 4)) !== 0;
+        const isWaypoint = (encodedNode & (1 << 5)) !== 0;
 
         // Create a new Cell instance with decoded properties
-        grid[y][x] = new Cell(x, y, isWall, isStart, isEnd, isPath, visited);
+        grid[y][x] = new Cell(x, y, isWall, isStart, isEnd, isPath, visited, isWaypoint);
       }
     }
     return grid;
   }
 
-  async decodePath(encodedPath: Int32Array): Promise<Map<String, Point>> {
-    const pathMap: Map<String, Point> = new Map();
-    const numSegments = encodedPath.length / 4; // 4 values per segment (x1, y1, x2, y2)
-
-    for (let i = 0; i < numSegments; i++) {
-      const index = i * 4;
-      const startPoint = new Point(encodedPath[index], encodedPath[index + 1]);
-      const endPoint = new Point(encodedPath[index + 2], encodedPath[index + 3]);
-
-      // Use the startPoint as the key and endPoint as the value
-      // The toString method of Point will be automatically called when it's used as a key
-      pathMap.set(startPoint.toString(), endPoint);
+  // The route is encoded in order as (x, y) pairs, a cell it passes twice appears twice
+  async decodePath(encodedPath: Int32Array): Promise<Point[]> {
+    const path: Point[] = [];
+    for (let i = 0; i + 1 < encodedPath.length; i += 2) {
+      path.push(new Point(encodedPath[i], encodedPath[i + 1]));
     }
-    return pathMap;
+    return path;
   }
 
 }